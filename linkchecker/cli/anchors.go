@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// anchorEntry holds the anchors of a file as of its last modification
+type anchorEntry struct {
+	modTime time.Time
+	size    int64
+	anchors []string
+}
+
+// anchorIndex parses the files that anchor links point to once and remembers their
+// anchors until the file changes. It is safe for concurrent use by validator workers.
+type anchorIndex struct {
+	mu      sync.Mutex
+	entries map[string]anchorEntry
+}
+
+// documentAnchors is shared by all checks, so that each target is parsed once
+var documentAnchors = &anchorIndex{entries: make(map[string]anchorEntry)}
+
+// lookup returns the anchors of a Markdown or HTML file. Other files have no
+// anchors that could be checked, so ok is false for them.
+func (a *anchorIndex) lookup(path string) ([]string, bool) {
+	if !isSupportedFile(path) {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	a.mu.Lock()
+	entry, found := a.entries[path]
+	a.mu.Unlock()
+	if found && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.anchors, true
+	}
+
+	doc, err := parseDocument(path)
+	if err != nil {
+		logger.Debug("not checking anchors", "source", path, "error", err)
+		return nil, false
+	}
+	a.mu.Lock()
+	a.entries[path] = anchorEntry{modTime: info.ModTime(), size: info.Size(), anchors: doc.Anchors}
+	a.mu.Unlock()
+	return doc.Anchors, true
+}
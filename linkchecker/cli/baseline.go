@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// baselineVersion is bumped when the file format changes incompatibly
+	baselineVersion     = 1
+	defaultBaselineFile = ".linkchecker-baseline.json"
+)
+
+// Baseline is a snapshot of known broken links. Entries are keyed by source and
+// URL but not by line, so that edits elsewhere in a file keep them matching.
+type Baseline struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry is a known broken link
+type BaselineEntry struct {
+	Source    string `json:"source"`
+	URL       string `json:"url"`
+	ErrorKind string `json:"error_kind,omitempty"`
+}
+
+func (e BaselineEntry) key() string {
+	return e.Source + "\x00" + e.URL
+}
+
+// keys returns the set of entry keys
+func (b Baseline) keys() map[string]bool {
+	known := make(map[string]bool, len(b.Entries))
+	for _, entry := range b.Entries {
+		known[entry.key()] = true
+	}
+	return known
+}
+
+// baselineEntryFor returns the entry of a result. Local paths are stored
+// relative to the working directory so that the file can be shared.
+func baselineEntryFor(result Result) BaselineEntry {
+	return BaselineEntry{Source: displaySource(result.Source), URL: result.URL, ErrorKind: result.ErrorKind}
+}
+
+// newBaseline records every broken link, once per source and URL
+func newBaseline(results []Result) Baseline {
+	baseline := Baseline{Version: baselineVersion, Entries: []BaselineEntry{}}
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if result.Status != "invalid" {
+			continue
+		}
+		entry := baselineEntryFor(result)
+		if !seen[entry.key()] {
+			seen[entry.key()] = true
+			baseline.Entries = append(baseline.Entries, entry)
+		}
+	}
+	sort.Slice(baseline.Entries, func(i, j int) bool {
+		a, b := baseline.Entries[i], baseline.Entries[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.URL < b.URL
+	})
+	return baseline
+}
+
+func loadBaseline(path string) (Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Baseline{}, fmt.Errorf("error reading baseline: %w", err)
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return Baseline{}, fmt.Errorf("error parsing baseline %s: %w", path, err)
+	}
+	if baseline.Version != baselineVersion {
+		return Baseline{}, fmt.Errorf("unsupported baseline version %d in %s: recreate it with 'linkchecker baseline create'", baseline.Version, path)
+	}
+	return baseline, nil
+}
+
+func writeBaseline(path string, baseline Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing baseline: %w", err)
+	}
+	return nil
+}
+
+// applyBaseline marks broken links that are in the baseline and collects the
+// entries that no longer match a broken link as stale
+func applyBaseline(output *Output, baseline Baseline) {
+	known := baseline.keys()
+
+	// Suppressed and skipped links were not checked, so like in diff they are
+	// neither fixed nor broken
+	stillBroken := make(map[string]bool)
+	unchecked := make(map[string]bool)
+	for i, result := range output.Results {
+		if result.Status == "skipped" || result.Status == "suppressed" {
+			unchecked[baselineEntryFor(result).key()] = true
+		}
+		if result.Status != "invalid" {
+			continue
+		}
+		key := baselineEntryFor(result).key()
+		if known[key] {
+			output.Results[i].Baselined = true
+			stillBroken[key] = true
+		}
+	}
+
+	// Only entries within the checked inputs can be judged; others were not looked at
+	for _, entry := range baseline.Entries {
+		if !stillBroken[entry.key()] && !unchecked[entry.key()] && inCheckedInputs(entry.Source) {
+			output.Stale = append(output.Stale, entry)
+		}
+	}
+}
+
+// inCheckedInputs reports whether a baseline source lies within the paths or URLs of this run
+func inCheckedInputs(source string) bool {
+	// Existing files that were skipped, e.g. by --changed-since or --exclude, were not looked at
+	if !isURL(source) && !checkedSources[source] {
+		if _, err := os.Stat(source); err == nil {
+			return false
+		}
+	}
+	for _, inputURL := range config.InputURLs {
+		if source == inputURL {
+			return true
+		}
+	}
+	for _, inputPath := range config.InputPaths {
+		root := displaySource(inputPath)
+		if root == "." || source == root || strings.HasPrefix(source, root+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func newBaselineCommand() *cobra.Command {
+	baselineCmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Manage the baseline of known broken links",
+		Long: `A baseline records known broken links, so that a run with --baseline only
+fails on broken links that are not in it yet.`,
+	}
+
+	var file string
+	createCmd := &cobra.Command{
+		Use:   "create [paths or URLs...]",
+		Short: "Check links and record every broken link in a baseline file",
+		Example: `  linkchecker baseline create ./docs
+  linkchecker --baseline=.linkchecker-baseline.json ./docs`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := prepareCheck(args); err != nil {
+				return err
+			}
+
+			startProgress(false)
+			output, err := checkLinks()
+			stopProgress()
+			if err != nil {
+				return err
+			}
+
+			baseline := newBaseline(output.Results)
+			if dir := filepath.Dir(file); dir != "." {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return fmt.Errorf("error creating directory for %s: %w", file, err)
+				}
+			}
+			if err := writeBaseline(file, baseline); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Recorded %d broken links in %s\n", len(baseline.Entries), file)
+			return nil
+		},
+	}
+	addCheckFlags(createCmd)
+	addSkipFlags(createCmd)
+	createCmd.Flags().StringVarP(&file, "file", "f", defaultBaselineFile, "Baseline file to write")
+
+	baselineCmd.AddCommand(createCmd)
+	return baselineCmd
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBaseline(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	broken := []Result{
+		{URL: "./gone.md", Status: "invalid", ErrorKind: "file-not-found", Source: "docs/b.md", Line: 4},
+		{URL: "https://old.example", Status: "invalid", ErrorKind: "dns", Source: "docs/a.md", Line: 2},
+		{URL: "https://old.example", Status: "invalid", ErrorKind: "dns", Source: "docs/a.md", Line: 9},
+		{URL: "https://ok.example", Status: "valid", Source: "docs/a.md", Line: 5},
+	}
+	baseline := newBaseline(broken)
+	want := []BaselineEntry{
+		{Source: "docs/a.md", URL: "https://old.example", ErrorKind: "dns"},
+		{Source: "docs/b.md", URL: "./gone.md", ErrorKind: "file-not-found"},
+	}
+	if !reflect.DeepEqual(baseline.Entries, want) {
+		t.Fatalf("unexpected entries:\n got: %+v\nwant: %+v", baseline.Entries, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := writeBaseline(path, baseline); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadBaseline(path)
+	if err != nil || !reflect.DeepEqual(loaded, baseline) {
+		t.Fatalf("baseline did not round-trip (%v): %+v", err, loaded)
+	}
+
+	// The link in docs/b.md was fixed, one in docs/a.md moved and a new one broke.
+	// An entry outside the checked paths is neither matched nor stale, and neither
+	// is one for a link that is now suppressed, since it was not checked.
+	loaded.Entries = append(loaded.Entries, BaselineEntry{Source: "other/c.md", URL: "./x.md"},
+		BaselineEntry{Source: "docs/a.md", URL: "https://muted.example"})
+	config.InputPaths, config.InputURLs = []string{"docs"}, nil
+	output := Output{Results: []Result{
+		{URL: "https://old.example", Status: "invalid", Source: "docs/a.md", Line: 12},
+		{URL: "https://new.example", Status: "invalid", Source: "docs/a.md", Line: 14},
+		{URL: "./gone.md", Status: "valid", Source: "docs/b.md", Line: 4},
+		{URL: "https://muted.example", Status: "suppressed", Reason: "flaky", Source: "docs/a.md", Line: 20},
+	}}
+	applyBaseline(&output, loaded)
+
+	if !output.Results[0].Baselined || output.Results[1].Baselined {
+		t.Errorf("unexpected baselined flags: %+v", output.Results)
+	}
+	if len(output.Stale) != 1 || output.Stale[0].Source != "docs/b.md" {
+		t.Errorf("expected docs/b.md to be stale, got %+v", output.Stale)
+	}
+
+	summarize(&output, time.Now())
+	if output.Summary.Invalid != 2 || output.Summary.Baselined != 1 {
+		t.Errorf("unexpected summary: %+v", output.Summary)
+	}
+	if err := brokenLinksError(output); err == nil {
+		t.Error("expected the new broken link to fail the run")
+	}
+	output.Results = output.Results[:1]
+	summarize(&output, time.Now())
+	if err := brokenLinksError(output); err != nil {
+		t.Errorf("baselined links alone should not fail the run: %v", err)
+	}
+}
+
+func TestLoadBaseline_Version(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := writeBaseline(path, Baseline{Version: baselineVersion + 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBaseline(path); err == nil {
+		t.Error("expected an error for an unsupported version")
+	}
+}
+
+func TestFinishOutput_BaselineBeforeOnlyDead(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	baseline := Baseline{Version: baselineVersion, Entries: []BaselineEntry{
+		{Source: "docs/a.md", URL: "https://nonexistent.invalid/"},
+		{Source: "docs/a.md", URL: "./gone.md"},
+	}}
+	if err := writeBaseline(path, baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	config.InputPaths, config.InputURLs = []string{"docs"}, nil
+	config.Baseline, config.OnlyDead = path, true
+	output := Output{Results: []Result{
+		{URL: "https://nonexistent.invalid/", Status: "skipped", Source: "docs/a.md"},
+		{URL: "./gone.md", Status: "invalid", Source: "docs/a.md"},
+	}}
+	if err := finishOutput(&output, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output.Stale) != 0 {
+		t.Errorf("expected no stale entries for a skipped link, got %+v", output.Stale)
+	}
+	if len(output.Results) != 1 || !output.Results[0].Baselined || output.Summary.Baselined != 1 {
+		t.Errorf("unexpected results: %+v", output.Results)
+	}
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+)
+
+// changedFiles holds the absolute paths of the files selected by --changed-since;
+// nil means every file is checked
+var changedFiles map[string]bool
+
+// gitChange is a file that Git reports as changed since a ref
+type gitChange struct {
+	// Status is the first letter of the Git status: A, C, D, M, R or T
+	Status byte
+	// Path is the current path, OldPath the path before a rename or copy
+	Path    string
+	OldPath string
+}
+
+// isSelected reports whether a file is checked in this run
+func isSelected(path string) bool {
+	if changedFiles == nil {
+		return true
+	}
+	abs, err := filepath.Abs(path)
+	return err == nil && changedFiles[abs]
+}
+
+// selectChangedFiles restricts the check to files changed since config.ChangedSince and,
+// with --include-referrers, to files linking to changed, removed or renamed files
+func selectChangedFiles() error {
+	changedFiles = nil
+	if config.ChangedSince == "" {
+		return nil
+	}
+
+	root, changes, err := gitChanges(config.ChangedSince)
+	if err != nil {
+		return err
+	}
+
+	selected := make(map[string]bool)
+	// Targets are every path a link may have pointed to, including removed ones
+	targets := make(map[string]bool)
+	for _, change := range changes {
+		path := filepath.Join(root, filepath.FromSlash(change.Path))
+		targets[path] = true
+		if change.OldPath != "" {
+			targets[filepath.Join(root, filepath.FromSlash(change.OldPath))] = true
+		}
+		if change.Status != 'D' {
+			selected[path] = true
+		}
+	}
+	logger.Debug("changed files", "ref", config.ChangedSince, "files", len(changes))
+
+	if config.IncludeReferrers {
+		referrers, err := findReferrers(targets)
+		if err != nil {
+			return err
+		}
+		for path := range referrers {
+			selected[path] = true
+		}
+	}
+
+	changedFiles = selected
+	return nil
+}
+
+// gitChanges asks Git for the files changed between the merge base of ref and HEAD and
+// the working tree. Paths are relative to the returned repository root.
+func gitChanges(ref string) (string, []gitChange, error) {
+	cdup, err := git("rev-parse", "--show-cdup")
+	if err != nil {
+		return "", nil, err
+	}
+	// The root is derived from the working directory so that it matches the input paths
+	root, err := filepath.Abs(strings.TrimSpace(string(cdup)))
+	if err != nil {
+		return "", nil, err
+	}
+
+	base, err := git("merge-base", ref, "HEAD")
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot find merge base of %s and HEAD: %w", ref, err)
+	}
+	diff, err := git("diff", "--name-status", "-M", "-z", strings.TrimSpace(string(base)))
+	if err != nil {
+		return "", nil, err
+	}
+	changes, err := parseNameStatus(diff)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// New files that are not committed yet count as added
+	untracked, err := git("ls-files", "--others", "--exclude-standard", "--full-name", "-z", "--", ":/")
+	if err != nil {
+		return "", nil, err
+	}
+	for _, path := range strings.Split(string(untracked), "\x00") {
+		if path != "" {
+			changes = append(changes, gitChange{Status: 'A', Path: path})
+		}
+	}
+	return root, changes, nil
+}
+
+// git runs a Git command in the working directory and returns its standard output
+func git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// parseNameStatus parses the output of git diff --name-status -z. Renames and copies
+// carry a similarity score and both the old and the new path.
+func parseNameStatus(out []byte) ([]gitChange, error) {
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	var changes []gitChange
+	for i := 0; i < len(fields); i++ {
+		status := fields[i]
+		if status == "" {
+			continue
+		}
+		change := gitChange{Status: status[0]}
+		if change.Status == 'R' || change.Status == 'C' {
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("unexpected git diff output: %q", status)
+			}
+			change.OldPath, change.Path = fields[i+1], fields[i+2]
+			i += 2
+		} else {
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("unexpected git diff output: %q", status)
+			}
+			change.Path = fields[i+1]
+			i++
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// findReferrers returns the files within the input paths that link to one of the targets
+func findReferrers(targets map[string]bool) (map[string]bool, error) {
+	referrers := make(map[string]bool)
+	for _, inputPath := range config.InputPaths {
+		info, err := os.Stat(inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("path does not exist: %s", inputPath)
+		}
+		siteRoot := siteRootFor(inputPath, info)
+
+		err = walkFiles(inputPath, func(path string) error {
+			links, err := linksTo(path, siteRoot, targets)
+			if err != nil || !links {
+				return err
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			logger.Debug("file links to changed file", "source", path)
+			referrers[abs] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return referrers, nil
+}
+
+// linksTo reports whether a file contains a local link to one of the targets
+func linksTo(filePath, siteRoot string, targets map[string]bool) (bool, error) {
+	doc, err := parseDocument(filePath)
+	if err != nil {
+		return false, fmt.Errorf("error extracting links from %s: %w", filePath, err)
+	}
+	opts := validator.Options{BasePath: filepath.Dir(filePath), SiteRoot: siteRoot}
+	for _, link := range doc.Links {
+		if link.URL == "" || isURL(link.URL) || hasUncheckableScheme(link.URL) {
+			continue
+		}
+		target := validator.ResolvePath(link.URL, opts)
+		if target == "" {
+			continue
+		}
+		target, err := filepath.Abs(target)
+		if err != nil {
+			return false, err
+		}
+		if targets[target] {
+			return true, nil
+		}
+		// A link to a directory is served by its index page
+		for _, name := range indexFilesFor(filePath) {
+			if targets[filepath.Join(target, name)] {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	out := "M\x00docs/a.md\x00R087\x00docs/old.md\x00docs/new.md\x00D\x00gone.md\x00A\x00with space.md\x00"
+	changes, err := parseNameStatus([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gitChange{
+		{Status: 'M', Path: "docs/a.md"},
+		{Status: 'R', Path: "docs/new.md", OldPath: "docs/old.md"},
+		{Status: 'D', Path: "gone.md"},
+		{Status: 'A', Path: "with space.md"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("unexpected changes:\n got: %+v\nwant: %+v", changes, want)
+	}
+
+	if _, err := parseNameStatus([]byte("R100\x00only-old.md\x00")); err == nil {
+		t.Error("expected an error for a truncated rename")
+	}
+}
+
+func TestSelectChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	defer func(saved Config) { config = saved }(config)
+	defer func() { changedFiles = nil }()
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	writeTestFile(t, ".", "docs/index.md", "[guide](guide.md)\n")
+	writeTestFile(t, ".", "docs/other.md", "[old](old.md) and [web](https://example.com/guide.md)\n")
+	writeTestFile(t, ".", "docs/old.md", "old page with enough content to be detected as a rename\n")
+	writeTestFile(t, ".", "docs/guide.md", "guide\n")
+	writeTestFile(t, ".", "docs/stable.md", "[index](index.md)\n")
+	run("init", "-q", "-b", "main")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	run("checkout", "-q", "-b", "feature")
+
+	run("mv", "docs/old.md", "docs/renamed.md")
+	writeTestFile(t, ".", "docs/guide.md", "changed guide\n")
+	writeTestFile(t, ".", "docs/added.md", "new\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "change")
+	writeTestFile(t, ".", "docs/draft.md", "untracked\n")
+
+	config.InputPaths = []string{"docs"}
+	config.ChangedSince = "main"
+	config.IncludeReferrers = false
+	if err := selectChangedFiles(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"docs/added.md", "docs/draft.md", "docs/guide.md", "docs/renamed.md"}
+	checkSelected(t, want)
+
+	config.IncludeReferrers = true
+	if err := selectChangedFiles(); err != nil {
+		t.Fatal(err)
+	}
+	checkSelected(t, append(want, "docs/index.md", "docs/other.md"))
+
+	config.ChangedSince = "no-such-ref"
+	if err := selectChangedFiles(); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
+
+// checkSelected verifies that exactly the given files under docs are selected
+func checkSelected(t *testing.T, want []string) {
+	t.Helper()
+	entries, err := os.ReadDir("docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := make(map[string]bool)
+	for _, name := range want {
+		wanted[name] = true
+	}
+	for _, entry := range entries {
+		name := "docs/" + entry.Name()
+		if isSelected(name) != wanted[name] {
+			t.Errorf("%s: selected = %v, want %v", name, isSelected(name), wanted[name])
+		}
+	}
+}
@@ -1,14 +1,17 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,8 +30,49 @@ type Config struct {
 	InputPaths  []string
 	InputURLs   []string
 	IgnoreRegex []*regexp.Regexp
-	Workers     int
-	Debug       bool
+	// OutputList holds the raw --output values, Outputs the parsed targets
+	OutputList []string
+	Outputs    []OutputTarget
+	Workers    int
+	Debug      bool
+	// LogLevel and LogFormat configure diagnostics on stderr
+	LogLevel  string
+	LogFormat string
+	// Color is auto, always or never
+	Color    string
+	Quiet    bool
+	Verbose  bool
+	SiteRoot string
+	Dialect  string
+	// FrontMatterKeys lists the front matter fields (or path.Match patterns) checked as links
+	FrontMatterKeys []string
+	// GroupBy and SortBy control the order of results in every format
+	GroupBy string
+	SortBy  string
+	// Baseline is a file of known broken links that do not fail the run
+	Baseline string
+	// Template is the text/template file used by the template format
+	Template string
+	// MarkdownMaxSize caps the markdown output in bytes; 0 means no limit
+	MarkdownMaxSize int
+	// ChangedSince restricts the check to files changed since this Git ref
+	ChangedSince string
+	// IncludeReferrers also checks files that link to changed, removed or renamed files
+	IncludeReferrers bool
+	// Watch re-checks files as they change; CacheTTL is how long HTTP results are reused
+	Watch    bool
+	CacheTTL time.Duration
+	// Include and Exclude are doublestar globs relative to a scanned directory
+	Include []string
+	Exclude []string
+	// Hidden also walks hidden files and directories, NoIgnore disables ignore files
+	// and the skipping of vendor directories
+	Hidden   bool
+	NoIgnore bool
+	// Offline skips external links and ExternalOnly skips local file links; skipped
+	// links are reported with the status "skipped"
+	Offline      bool
+	ExternalOnly bool
 }
 
 // Result represents a link check result
@@ -37,19 +81,53 @@ type Result struct {
 	Status     string `json:"status"`
 	StatusCode int    `json:"status_code,omitempty"`
 	Error      string `json:"error,omitempty"`
+	ErrorKind  string `json:"error_kind,omitempty"`
 	Source     string `json:"source"`
 	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+	// RedirectURL is where the link ended up after following redirects
+	RedirectURL  string `json:"redirect_url,omitempty"`
+	RedirectCode int    `json:"redirect_code,omitempty"`
+	// Baselined marks an invalid link that is listed in the --baseline file
+	Baselined bool `json:"baselined,omitempty"`
+	// Reason explains why a link was not checked, e.g. the reason given in a
+	// linkcheck-disable comment
+	Reason string `json:"reason,omitempty"`
+}
+
+// Finding represents a lint issue found while parsing a source file
+type Finding struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Source  string `json:"source"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// OutputTarget is an additional report written to a file, given as --output format=path
+type OutputTarget struct {
+	Format string
+	// Path is the file to write, or "-" for stdout
+	Path string
 }
 
 // Output represents the final output structure
 type Output struct {
 	Summary struct {
-		Total    int    `json:"total"`
-		Valid    int    `json:"valid"`
-		Invalid  int    `json:"invalid"`
-		Duration string `json:"duration"`
+		Total      int `json:"total"`
+		Valid      int `json:"valid"`
+		Invalid    int `json:"invalid"`
+		Suppressed int `json:"suppressed,omitempty"`
+		Skipped    int `json:"skipped,omitempty"`
+		// Baselined counts the invalid links that are known from the baseline
+		Baselined int    `json:"baselined,omitempty"`
+		Duration  string `json:"duration"`
 	} `json:"summary"`
-	Results []Result `json:"results"`
+	Results  []Result  `json:"results"`
+	Findings []Finding `json:"findings,omitempty"`
+	// Stale lists baseline entries whose links are no longer broken
+	Stale []BaselineEntry `json:"stale,omitempty"`
 }
 
 var (
@@ -62,12 +140,15 @@ var (
 	rootCmd = &cobra.Command{
 		Use:   "linkchecker [paths or URLs...]",
 		Short: "A fast and reliable link checker for markdown files and web pages",
-		Long: `Link Checker is a command-line tool that validates links in markdown files
-and checks web pages for dead links. You can provide file paths, directories,
+		Long: `Link Checker is a command-line tool that validates links in markdown and HTML
+files and checks web pages for dead links. You can provide file paths, directories,
 or direct URLs to check.`,
 		Example: `  # Check markdown files
   linkchecker README.md
   linkchecker --recursive ./docs
+
+  # Check a generated static site without a web server
+  linkchecker --site-root=./public ./public
   
   # Check web pages for dead links
   linkchecker https://example.com
@@ -94,26 +175,47 @@ func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	// Define flags
-	rootCmd.Flags().BoolVarP(&config.Recursive, "recursive", "r", false,
-		"Recursively scan directories for markdown files")
-
-	rootCmd.Flags().StringSliceVar(&config.IgnoreList, "ignore", []string{},
-		"Comma-separated list of domains or regex patterns to ignore (e.g., 'example.com,*.test.local')")
-
-	rootCmd.Flags().DurationVar(&config.Timeout, "timeout", 30*time.Second,
-		"HTTP request timeout (e.g., 10s, 1m, 30s)")
+	addCheckFlags(rootCmd)
+	addSkipFlags(rootCmd)
 
 	rootCmd.Flags().BoolVar(&config.OnlyDead, "only-dead", false,
 		"Only show dead/broken links in output")
 
 	rootCmd.Flags().StringVar(&config.Format, "format", "text",
-		"Output format: 'text' or 'json'")
+		"Output format: "+strings.Join(formatNames(), ", "))
 
-	rootCmd.Flags().IntVar(&config.Workers, "workers", 10,
-		"Number of concurrent workers for link validation (default: 10)")
+	rootCmd.Flags().StringArrayVar(&config.OutputList, "output", []string{},
+		"Also write a report as format=path (repeatable, e.g. --output sarif=links.sarif --output html=report.html)")
+
+	rootCmd.Flags().StringVar(&config.GroupBy, "group-by", "source",
+		"Group results by: "+strings.Join(sortedNames(groupKeys), ", "))
 
-	rootCmd.Flags().BoolVar(&config.Debug, "debug", false,
-		"Enable debug output")
+	rootCmd.Flags().StringVar(&config.SortBy, "sort", "source",
+		"Sort results within a group by: "+strings.Join(sortedNames(sortKeys), ", ")+" (prefix with - to reverse)")
+
+	rootCmd.Flags().StringVar(&config.Color, "color", "auto",
+		"Color terminal output: 'auto' (terminals only, honors NO_COLOR), 'always' or 'never'")
+
+	rootCmd.Flags().BoolVarP(&config.Quiet, "quiet", "q", false,
+		"Only print broken links and the summary, without progress")
+
+	rootCmd.Flags().BoolVarP(&config.Verbose, "verbose", "v", false,
+		"Print the configuration and details such as durations and redirects")
+
+	rootCmd.Flags().StringVar(&config.Baseline, "baseline", "",
+		"Baseline file of known broken links; only new broken links fail the run")
+
+	rootCmd.Flags().StringVar(&config.Template, "template", "",
+		"Go text/template file used with --format=template")
+
+	rootCmd.Flags().IntVar(&config.MarkdownMaxSize, "markdown-max-size", defaultMarkdownMaxSize,
+		"Maximum size in bytes of markdown output, e.g. to fit a pull request comment (0 for no limit)")
+
+	rootCmd.Flags().BoolVar(&config.Watch, "watch", false,
+		"Keep running and re-check Markdown and HTML files when they change")
+
+	rootCmd.Flags().DurationVar(&config.CacheTTL, "cache-ttl", 10*time.Minute,
+		"With --watch, how long results of external links are reused before they are checked again")
 
 	// Add version command
 	rootCmd.AddCommand(&cobra.Command{
@@ -126,11 +228,140 @@ func init() {
 		},
 	})
 
+	rootCmd.AddCommand(newBaselineCommand())
+	rootCmd.AddCommand(newDiffCommand())
+	rootCmd.AddCommand(newInspectCommand())
+	rootCmd.AddCommand(newFixCommand())
+	rootCmd.AddCommand(newExtractCommand())
+
 	// Add help examples
 	rootCmd.SetHelpTemplate(getHelpTemplate())
 }
 
+// addCheckFlags defines the flags that control which links are found and how they
+// are checked. They are shared by every command that checks links.
+func addCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&config.Recursive, "recursive", "r", false,
+		"Recursively scan directories for markdown and HTML files")
+
+	cmd.Flags().StringSliceVar(&config.IgnoreList, "ignore", []string{},
+		"Comma-separated list of domains or regex patterns to ignore (e.g., 'example.com,*.test.local')")
+
+	cmd.Flags().DurationVar(&config.Timeout, "timeout", 30*time.Second,
+		"HTTP request timeout (e.g., 10s, 1m, 30s)")
+
+	cmd.Flags().IntVar(&config.Workers, "workers", 10,
+		"Number of concurrent workers for link validation (default: 10)")
+
+	cmd.Flags().BoolVar(&config.Debug, "debug", false,
+		"Enable debug logging (same as --log-level=debug)")
+
+	cmd.Flags().StringVar(&config.LogLevel, "log-level", "warn",
+		"Log level for diagnostics on stderr: 'debug', 'info', 'warn' or 'error'")
+
+	cmd.Flags().StringVar(&config.LogFormat, "log-format", "text",
+		"Log format: 'text' or 'json'")
+
+	cmd.Flags().StringVar(&config.SiteRoot, "site-root", "",
+		"Directory that root-relative links (/path) resolve against (default: the scanned directory)")
+
+	cmd.Flags().StringVar(&config.Dialect, "markdown-dialect", "commonmark",
+		"Markdown dialect: 'commonmark' or 'gfm' (GitHub Flavored Markdown)")
+
+	cmd.Flags().StringSliceVar(&config.FrontMatterKeys, "front-matter-keys", parser.DefaultFrontMatterKeys,
+		"Comma-separated front matter keys or patterns whose values are checked as links (e.g., 'canonical,params.*_url')")
+
+	cmd.Flags().StringArrayVar(&config.Include, "include", []string{},
+		"Only check files in scanned directories matching this glob (repeatable, e.g., 'docs/**/*.md')")
+
+	cmd.Flags().StringArrayVar(&config.Exclude, "exclude", []string{},
+		"Skip files and directories in scanned directories matching this glob (repeatable, e.g., '**/drafts/**')")
+
+	cmd.Flags().BoolVar(&config.Hidden, "hidden", false,
+		"Also scan hidden files and directories")
+
+	cmd.Flags().BoolVar(&config.NoIgnore, "no-ignore", false,
+		"Don't honor .gitignore and .ignore files and don't skip node_modules and vendor directories")
+
+	cmd.Flags().StringVar(&config.ChangedSince, "changed-since", "",
+		"Only check files that Git reports as changed or added since this ref (e.g., origin/main)")
+
+	cmd.Flags().BoolVar(&config.IncludeReferrers, "include-referrers", false,
+		"With --changed-since, also check files that link to changed, removed or renamed files")
+}
+
+// addSkipFlags defines the flags that leave whole classes of links unchecked. They
+// are shared by the commands that report check results.
+func addSkipFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&config.Offline, "offline", false,
+		"Skip http(s) links and only check local files and anchors, without network access")
+
+	cmd.Flags().BoolVar(&config.ExternalOnly, "external-only", false,
+		"Only check http(s) links and skip local files and anchors")
+}
+
 func runLinkChecker(cmd *cobra.Command, args []string) error {
+	if err := prepareCheck(args); err != nil {
+		return err
+	}
+
+	// A template implies the template format
+	if config.Template != "" && !cmd.Flags().Changed("format") {
+		config.Format = "template"
+	}
+
+	// Validate format
+	if _, ok := outputFormatters[config.Format]; !ok {
+		return fmt.Errorf("invalid format '%s': must be one of %s", config.Format, strings.Join(formatNames(), ", "))
+	}
+
+	if err := parseOutputTargets(); err != nil {
+		return err
+	}
+
+	// Catch template errors before spending time on checking links
+	if usesFormat("template") {
+		if config.Template == "" {
+			return fmt.Errorf("--format=template requires --template")
+		}
+		if _, err := loadTemplate(config.Template); err != nil {
+			return err
+		}
+	} else if config.Template != "" {
+		return fmt.Errorf("--template can only be used with the template format")
+	}
+
+	if err := validateColor(config.Color); err != nil {
+		return err
+	}
+	if config.Quiet && config.Verbose {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+
+	if err := validateOrder(config.GroupBy, config.SortBy); err != nil {
+		return err
+	}
+
+	if config.MarkdownMaxSize < 0 {
+		return fmt.Errorf("invalid markdown max size %d: must not be negative", config.MarkdownMaxSize)
+	}
+
+	// The configuration goes to stderr so that it never mixes with reports
+	if config.Verbose {
+		printConfig(os.Stderr)
+	}
+
+	if config.Watch {
+		return runWatch()
+	}
+
+	// Run actual link checking
+	return runRealLinkChecker()
+}
+
+// prepareCheck sorts the arguments into paths and URLs and validates the flags
+// added by addCheckFlags
+func prepareCheck(args []string) error {
 	// Set default if no arguments provided
 	if len(args) == 0 {
 		config.InputPaths = []string{"."}
@@ -140,7 +371,8 @@ func runLinkChecker(cmd *cobra.Command, args []string) error {
 		config.InputURLs = []string{}
 
 		for _, arg := range args {
-			if isURL(arg) {
+			// A path such as //srv/docs is a file path, not a page to fetch
+			if isURL(arg) && !strings.HasPrefix(arg, "//") {
 				config.InputURLs = append(config.InputURLs, arg)
 			} else {
 				config.InputPaths = append(config.InputPaths, arg)
@@ -148,9 +380,14 @@ func runLinkChecker(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Validate format
-	if config.Format != "text" && config.Format != "json" {
-		return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", config.Format)
+	// Validate markdown dialect
+	if config.Dialect != string(parser.DialectCommonMark) && config.Dialect != string(parser.DialectGFM) {
+		return fmt.Errorf("invalid markdown dialect '%s': must be 'commonmark' or 'gfm'", config.Dialect)
+	}
+
+	var err error
+	if logger, err = newLogger(os.Stderr); err != nil {
+		return err
 	}
 
 	// Compile ignore patterns into regex
@@ -158,18 +395,30 @@ func runLinkChecker(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error compiling ignore patterns: %w", err)
 	}
 
-	// Print configuration in verbose mode
-	if config.Format == "text" {
-		printConfig()
+	if err := validateGlobs(); err != nil {
+		return err
 	}
 
-	// Run actual link checking
-	return runRealLinkChecker()
+	if config.IncludeReferrers && config.ChangedSince == "" {
+		return fmt.Errorf("--include-referrers requires --changed-since")
+	}
+	if config.Offline && config.ExternalOnly {
+		return fmt.Errorf("--offline and --external-only cannot be used together")
+	}
+	if config.Offline && len(config.InputURLs) > 0 {
+		return fmt.Errorf("--offline cannot check URLs: %s", strings.Join(config.InputURLs, ", "))
+	}
+	if err := selectChangedFiles(); err != nil {
+		return fmt.Errorf("error finding changed files: %w", err)
+	}
+	return nil
 }
 
+// isURL reports whether input is an absolute URL or a protocol-relative link
+// (//host/path), which is fetched with the scheme of the page it appears on
 func isURL(input string) bool {
 	u, err := url.Parse(input)
-	return err == nil && u.Scheme != "" && u.Host != ""
+	return err == nil && u.Host != "" && (u.Scheme != "" || strings.HasPrefix(input, "//"))
 }
 
 func compileIgnorePatterns() error {
@@ -196,167 +445,460 @@ func compileIgnorePatterns() error {
 	return nil
 }
 
-func printConfig() {
-	fmt.Printf("Link Checker Configuration:\n")
+// parseOutputTargets validates the --output values
+func parseOutputTargets() error {
+	config.Outputs = make([]OutputTarget, 0, len(config.OutputList))
+	for _, value := range config.OutputList {
+		format, path, ok := strings.Cut(value, "=")
+		if !ok || format == "" || path == "" {
+			return fmt.Errorf("invalid output '%s': expected format=path", value)
+		}
+		if _, ok := outputFormatters[format]; !ok {
+			return fmt.Errorf("invalid output format '%s': must be one of %s", format, strings.Join(formatNames(), ", "))
+		}
+		if path == "-" && stdoutFormat() != config.Format {
+			return fmt.Errorf("only one report can be written to stdout")
+		}
+		config.Outputs = append(config.Outputs, OutputTarget{Format: format, Path: path})
+	}
+	return nil
+}
+
+// stdoutFormat returns the format written to stdout: the --format value,
+// unless an --output target claims stdout with "-"
+func stdoutFormat() string {
+	for _, target := range config.Outputs {
+		if target.Path == "-" {
+			return target.Format
+		}
+	}
+	return config.Format
+}
+
+// usesFormat reports whether a format is written to stdout or to any output file
+func usesFormat(format string) bool {
+	if stdoutFormat() == format {
+		return true
+	}
+	for _, target := range config.Outputs {
+		if target.Format == format {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOutputFiles reports whether any --output target is a file
+func hasOutputFiles() bool {
+	for _, target := range config.Outputs {
+		if target.Path != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeOutputTargets writes the report for every --output file
+func writeOutputTargets(output Output) error {
+	for _, target := range config.Outputs {
+		if target.Path == "-" {
+			continue
+		}
+		if dir := filepath.Dir(target.Path); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("error creating directory for %s: %w", target.Path, err)
+			}
+		}
+		file, err := os.Create(target.Path)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		formatter, ok := fileFormatters[target.Format]
+		if !ok {
+			formatter = outputFormatters[target.Format]
+		}
+		err = formatter(file, output)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("error writing %s output to %s: %w", target.Format, target.Path, err)
+		}
+	}
+	return nil
+}
+
+func printConfig(w io.Writer) {
+	fmt.Fprintf(w, "Link Checker Configuration:\n")
 	if len(config.InputPaths) > 0 {
-		fmt.Printf("  File Paths: %v\n", config.InputPaths)
+		fmt.Fprintf(w, "  File Paths: %v\n", config.InputPaths)
 	}
 	if len(config.InputURLs) > 0 {
-		fmt.Printf("  URLs to Check: %v\n", config.InputURLs)
+		fmt.Fprintf(w, "  URLs to Check: %v\n", config.InputURLs)
+	}
+	fmt.Fprintf(w, "  Recursive: %v\n", config.Recursive)
+	fmt.Fprintf(w, "  Timeout: %v\n", config.Timeout)
+	fmt.Fprintf(w, "  Only Dead Links: %v\n", config.OnlyDead)
+	fmt.Fprintf(w, "  Output Format: %s\n", config.Format)
+	for _, target := range config.Outputs {
+		fmt.Fprintf(w, "  Output File: %s (%s)\n", target.Path, target.Format)
+	}
+	fmt.Fprintf(w, "  Markdown Dialect: %s\n", config.Dialect)
+	fmt.Fprintf(w, "  Workers: %d\n", config.Workers)
+	if config.SiteRoot != "" {
+		fmt.Fprintf(w, "  Site Root: %s\n", config.SiteRoot)
 	}
-	fmt.Printf("  Recursive: %v\n", config.Recursive)
-	fmt.Printf("  Timeout: %v\n", config.Timeout)
-	fmt.Printf("  Only Dead Links: %v\n", config.OnlyDead)
-	fmt.Printf("  Output Format: %s\n", config.Format)
-	fmt.Printf("  Workers: %d\n", config.Workers)
 	if len(config.IgnoreList) > 0 {
-		fmt.Printf("  Ignore Patterns: %v\n", config.IgnoreList)
+		fmt.Fprintf(w, "  Ignore Patterns: %v\n", config.IgnoreList)
+	}
+	if len(config.Include) > 0 {
+		fmt.Fprintf(w, "  Include: %v\n", config.Include)
 	}
-	fmt.Println()
+	if len(config.Exclude) > 0 {
+		fmt.Fprintf(w, "  Exclude: %v\n", config.Exclude)
+	}
+	if config.Offline {
+		fmt.Fprintf(w, "  Offline: skipping http(s) links\n")
+	}
+	if config.ExternalOnly {
+		fmt.Fprintf(w, "  External Only: skipping local links\n")
+	}
+	if config.ChangedSince != "" {
+		fmt.Fprintf(w, "  Changed Since: %s (%d files, referrers: %v)\n", config.ChangedSince, len(changedFiles), config.IncludeReferrers)
+	}
+	fmt.Fprintln(w)
 }
 
 func runRealLinkChecker() error {
 	start := time.Now()
-	results := []Result{}
 
-	// Process file paths
-	for _, inputPath := range config.InputPaths {
-		fileResults, err := processPath(inputPath)
-		if err != nil {
-			return fmt.Errorf("error processing path '%s': %w", inputPath, err)
+	var stream resultStream
+	if newStream, ok := streamingFormatters[stdoutFormat()]; ok {
+		// Streamed results are written before the run ends, so the baseline is
+		// loaded up front and applied to each of them
+		if config.Baseline != "" {
+			baseline, err := loadBaseline(config.Baseline)
+			if err != nil {
+				return err
+			}
+			streamBaseline = baseline.keys()
+			defer func() { streamBaseline = nil }()
 		}
-		results = append(results, fileResults...)
+		stream = newStream(os.Stdout)
+		resultReporter = stream.Result
+		streamOnly = !hasOutputFiles()
+		defer func() { resultReporter, streamOnly = nil, false }()
 	}
 
-	// Process URLs
-	for _, inputURL := range config.InputURLs {
-		urlResults, err := processURL(inputURL)
+	startProgress(stream != nil)
+	defer stopProgress()
+
+	output, err := checkLinks()
+	if err != nil {
+		return err
+	}
+	if err := finishOutput(&output, start); err != nil {
+		return err
+	}
+
+	// Output results
+	stopProgress()
+	if err := writeOutputTargets(output); err != nil {
+		return err
+	}
+	if stream != nil {
+		err = stream.Finish(output.Findings, output.Summary.Duration)
+	} else {
+		err = outputFormatters[stdoutFormat()](os.Stdout, output)
+	}
+	if err != nil {
+		return err
+	}
+	return brokenLinksError(output)
+}
+
+// startProgress shows live progress on a terminal, unless streamed results or
+// log lines go to the same one
+func startProgress(streaming bool) {
+	if config.Quiet || !isTerminal(os.Stderr) || (streaming && isTerminal(os.Stdout)) ||
+		logger.Enabled(context.Background(), slog.LevelInfo) {
+		return
+	}
+	progressDisplay = newProgress(os.Stderr, palette(colorEnabled(config.Color, os.Stderr)))
+}
+
+// finishOutput applies the baseline, filters the checked results and adds the summary
+func finishOutput(output *Output, start time.Time) error {
+	// The baseline is applied to all results, so that skipped links are not taken
+	// for fixed ones when --only-dead drops them
+	if config.Baseline != "" {
+		baseline, err := loadBaseline(config.Baseline)
 		if err != nil {
-			return fmt.Errorf("error processing URL '%s': %w", inputURL, err)
+			return err
 		}
-		results = append(results, urlResults...)
+		applyBaseline(output, baseline)
 	}
 
 	// Filter results if only-dead is enabled
 	if config.OnlyDead {
 		filteredResults := make([]Result, 0)
-		for _, result := range results {
+		for _, result := range output.Results {
 			if result.Status == "invalid" {
 				filteredResults = append(filteredResults, result)
 			}
 		}
-		results = filteredResults
+		output.Results = filteredResults
 	}
 
-	// Create output
-	output := Output{
-		Results: results,
+	summarize(output, start)
+	return nil
+}
+
+// checkedSources holds the display paths of the files checked in this run
+var checkedSources map[string]bool
+
+// checkLinks checks all input paths and URLs and returns the results in order
+func checkLinks() (Output, error) {
+	checkedSources = make(map[string]bool)
+	results := []Result{}
+	var findings []Finding
+
+	// Process file paths
+	for _, inputPath := range config.InputPaths {
+		fileResults, fileFindings, err := processPath(inputPath)
+		if err != nil {
+			return Output{}, fmt.Errorf("error processing path '%s': %w", inputPath, err)
+		}
+		results = append(results, retainedResults(fileResults)...)
+		findings = append(findings, fileFindings...)
 	}
 
-	// Calculate summary
+	// Process URLs
+	for _, inputURL := range config.InputURLs {
+		urlResults, err := processURL(inputURL)
+		if err != nil {
+			return Output{}, fmt.Errorf("error processing URL '%s': %w", inputURL, err)
+		}
+		results = append(results, retainedResults(urlResults)...)
+	}
+
+	// Sort so that every run prints the same order
+	orderResults(results, config.GroupBy, config.SortBy)
+	orderFindings(findings)
+
+	return Output{Results: results, Findings: findings}, nil
+}
+
+// retainedResults returns the results to keep for the report. When they are only
+// streamed, valid and suppressed results are dropped once written; broken and
+// skipped ones are kept for the baseline and the exit code.
+func retainedResults(results []Result) []Result {
+	if !streamOnly {
+		return results
+	}
+	kept := results[:0]
+	for _, result := range results {
+		if result.Status == "invalid" || result.Status == "skipped" {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// summarize counts the results of a run
+func summarize(output *Output, start time.Time) {
 	valid := 0
 	invalid := 0
-	for _, result := range results {
-		if result.Status == "valid" {
+	suppressed := 0
+	skipped := 0
+	baselined := 0
+	for _, result := range output.Results {
+		switch result.Status {
+		case "valid":
 			valid++
-		} else {
+		case "suppressed":
+			suppressed++
+		case "skipped":
+			skipped++
+		default:
 			invalid++
+			if result.Baselined {
+				baselined++
+			}
 		}
 	}
 
-	output.Summary.Total = len(results)
+	output.Summary.Total = len(output.Results)
 	output.Summary.Valid = valid
 	output.Summary.Invalid = invalid
+	output.Summary.Suppressed = suppressed
+	output.Summary.Skipped = skipped
+	output.Summary.Baselined = baselined
 	output.Summary.Duration = time.Since(start).String()
+}
 
-	// Output results
-	if config.Format == "json" {
-		return outputJSON(output)
+// brokenLinksError fails the run when it found broken links that are not in the baseline
+func brokenLinksError(output Output) error {
+	broken := output.Summary.Invalid - output.Summary.Baselined
+	switch {
+	case broken == 0:
+		return nil
+	case output.Summary.Baselined > 0:
+		return fmt.Errorf("found %d new broken links (%d known broken links are in the baseline)", broken, output.Summary.Baselined)
+	default:
+		return fmt.Errorf("found %d broken links", broken)
 	}
-
-	return outputText(output)
 }
 
-func processPath(inputPath string) ([]Result, error) {
+func processPath(inputPath string) ([]Result, []Finding, error) {
 	var results []Result
+	var findings []Finding
 
 	// Check if path exists
 	info, err := os.Stat(inputPath)
 	if err != nil {
-		return nil, fmt.Errorf("path does not exist: %s", inputPath)
+		return nil, nil, fmt.Errorf("path does not exist: %s", inputPath)
 	}
 
-	if info.IsDir() {
-		// Process directory
-		err := filepath.Walk(inputPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-
-			if !info.IsDir() && isMarkdownFile(path) {
-				fileResults, err := processMarkdownFile(path)
-				if err != nil {
-					return err
-				}
-				results = append(results, fileResults...)
-			}
+	siteRoot := siteRootFor(inputPath, info)
 
+	err = walkFiles(inputPath, func(path string) error {
+		if !isSelected(path) {
 			return nil
-		})
-		if err != nil {
-			return nil, err
 		}
-	} else {
-		// Process single file
-		if isMarkdownFile(inputPath) {
-			fileResults, err := processMarkdownFile(inputPath)
-			if err != nil {
-				return nil, err
-			}
-			results = append(results, fileResults...)
+		fileResults, fileFindings, err := processFile(path, siteRoot)
+		if err != nil {
+			return err
 		}
+		results = append(results, retainedResults(fileResults)...)
+		findings = append(findings, fileFindings...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return results, nil
+	return results, findings, nil
 }
 
-func processMarkdownFile(filePath string) ([]Result, error) {
-	links, err := parser.ExtractLinksFromFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("error extracting links from %s: %w", filePath, err)
+// siteRootFor returns the directory that root-relative links in an input path resolve against
+func siteRootFor(inputPath string, info os.FileInfo) string {
+	// Root-relative links resolve against the scanned directory unless configured
+	if config.SiteRoot != "" {
+		return config.SiteRoot
 	}
+	if info.IsDir() {
+		return inputPath
+	}
+	return filepath.Dir(inputPath)
+}
 
-	// Filter ignored links
-	var filteredLinks []string
-	for _, link := range links {
-		if !IsURLIgnored(link) {
-			filteredLinks = append(filteredLinks, link)
-		}
+// parseDocument extracts the links of a markdown or HTML file
+func parseDocument(filePath string) (parser.Document, error) {
+	if isHTMLFile(filePath) {
+		return parser.ParseHTMLFile(filePath)
 	}
+	return parser.ParseMarkdownFile(filePath, parser.Options{
+		Dialect:         parser.Dialect(config.Dialect),
+		FrontMatterKeys: config.FrontMatterKeys,
+	})
+}
 
-	// Validate links
-	linkStatuses := validator.ValidateLinksAsync(filteredLinks, filepath.Dir(filePath), config.Timeout, config.Workers)
+// indexFilesFor returns the index pages that directory links in a file resolve to
+func indexFilesFor(filePath string) []string {
+	if isHTMLFile(filePath) {
+		// Directory links on a static site are served by their index page
+		return []string{"index.html", "index.htm"}
+	}
+	return nil
+}
 
-	var results []Result
-	for _, status := range linkStatuses {
-		result := Result{
-			URL:    status.Link,
-			Source: filePath,
+func processFile(filePath, siteRoot string) ([]Result, []Finding, error) {
+	opts := validator.Options{
+		BasePath:   filepath.Dir(filePath),
+		SiteRoot:   siteRoot,
+		Timeout:    config.Timeout,
+		Workers:    config.Workers,
+		Logger:     logger,
+		IndexFiles: indexFilesFor(filePath),
+		Cache:      resultCache,
+		Document:   filePath,
+		Anchors:    documentAnchors.lookup,
+	}
+
+	doc, err := parseDocument(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error extracting links from %s: %w", filePath, err)
+	}
+	checkedSources[displaySource(filePath)] = true
+	logger.Debug("parsed file", "source", filePath, "links", len(doc.Links), "findings", len(doc.Findings))
+
+	// Filter ignored and uncheckable links
+	var links []parser.Link
+	var uniqueLinks []string
+	seen := make(map[string]bool)
+	for _, link := range doc.Links {
+		if link.URL == "" || hasUncheckableScheme(link.URL) || IsURLIgnored(link.URL) {
+			continue
 		}
+		links = append(links, link)
+		// Suppressed and skipped links are reported but never requested
+		if !seen[link.URL] && !link.Suppressed && skipReason(link.URL) == "" {
+			seen[link.URL] = true
+			uniqueLinks = append(uniqueLinks, link.URL)
+		}
+	}
 
-		if status.Valid {
-			result.Status = "valid"
-			result.StatusCode = status.StatusCode
-		} else {
-			result.Status = "invalid"
-			result.Error = status.Reason
-			result.StatusCode = status.StatusCode
+	// Suppressed and skipped links are reported right away, the others once their
+	// check completes
+	results := make([]Result, len(links))
+	occurrences := make(map[string][]int)
+	for i, link := range links {
+		results[i] = Result{
+			URL:    link.URL,
+			Source: filePath,
+			Line:   link.Line,
+			Column: link.Column,
+		}
+		if link.Suppressed {
+			results[i].Status = "suppressed"
+			results[i].Reason = link.SuppressReason
+			reportResult(results[i])
+			continue
+		}
+		if reason := skipReason(link.URL); reason != "" {
+			results[i].Status = "skipped"
+			results[i].Reason = reason
+			reportResult(results[i])
+			continue
 		}
+		occurrences[link.URL] = append(occurrences[link.URL], i)
+	}
 
-		results = append(results, result)
+	// Validate each distinct link once
+	progressDisplay.queue(len(uniqueLinks))
+	opts.OnStart = progressDisplay.begin
+	opts.OnResult = func(status validator.LinkStatus) {
+		progressDisplay.finish(status.Link)
+		for _, i := range occurrences[status.Link] {
+			applyStatus(&results[i], status)
+			reportResult(results[i])
+		}
+	}
+	validator.ValidateLinksWithOptions(uniqueLinks, opts)
+
+	var findings []Finding
+	for _, f := range doc.Findings {
+		findings = append(findings, Finding{
+			Rule:    f.Rule,
+			Message: f.Message,
+			Source:  filePath,
+			Line:    f.Line,
+			Column:  f.Column,
+		})
 	}
 
-	return results, nil
+	return results, findings, nil
 }
 
 func processURL(inputURL string) ([]Result, error) {
@@ -379,9 +921,7 @@ func processURL(inputURL string) ([]Result, error) {
 	// Extract links from HTML content
 	links := parser.ExtractLinksFromHTML(content)
 
-	if config.Debug {
-		fmt.Printf("Debug: Found %d raw links in HTML\n", len(links))
-	}
+	logger.Debug("extracted links from page", "source", inputURL, "links", len(links))
 
 	// Convert relative URLs to absolute URLs
 	baseURL, err := url.Parse(inputURL)
@@ -394,11 +934,9 @@ func processURL(inputURL string) ([]Result, error) {
 		// Skip empty links, anchors, and javascript/mailto links
 		if link == "" ||
 			strings.HasPrefix(link, "#") ||
-			strings.HasPrefix(link, "javascript:") ||
-			strings.HasPrefix(link, "mailto:") ||
-			strings.HasPrefix(link, "tel:") {
-			if config.Debug && link != "" {
-				fmt.Printf("Debug: Skipping link: %s (anchor/javascript/mailto/tel)\n", link)
+			hasUncheckableScheme(link) {
+			if link != "" {
+				logger.Debug("skipping link", "source", inputURL, "url", link, "reason", "anchor or uncheckable scheme")
 			}
 			continue
 		}
@@ -406,30 +944,24 @@ func processURL(inputURL string) ([]Result, error) {
 		// Parse the link URL
 		linkURL, err := url.Parse(link)
 		if err != nil {
-			if config.Debug {
-				fmt.Printf("Debug: Failed to parse link: %s (error: %v)\n", link, err)
-			}
+			logger.Debug("skipping link", "source", inputURL, "url", link, "error", err)
 			continue // Skip invalid URLs
 		}
 
 		// Resolve relative URLs to absolute URLs
 		absoluteURL := baseURL.ResolveReference(linkURL)
 
-		if config.Debug {
-			fmt.Printf("Debug: %s -> %s\n", link, absoluteURL.String())
-		}
+		logger.Debug("resolved link", "source", inputURL, "url", link, "resolved", absoluteURL.String())
 
 		// Only include HTTP/HTTPS URLs for validation
 		if absoluteURL.Scheme == "http" || absoluteURL.Scheme == "https" {
 			absoluteLinks = append(absoluteLinks, absoluteURL.String())
-		} else if config.Debug {
-			fmt.Printf("Debug: Skipping non-HTTP(S) URL: %s (scheme: %s)\n", absoluteURL.String(), absoluteURL.Scheme)
+		} else {
+			logger.Debug("skipping link", "source", inputURL, "url", absoluteURL.String(), "reason", "scheme "+absoluteURL.Scheme)
 		}
 	}
 
-	if config.Debug {
-		fmt.Printf("Debug: %d links will be validated\n", len(absoluteLinks))
-	}
+	logger.Debug("validating links", "source", inputURL, "links", len(absoluteLinks))
 
 	// Filter ignored links
 	var filteredLinks []string
@@ -440,28 +972,75 @@ func processURL(inputURL string) ([]Result, error) {
 	}
 
 	// Validate links
-	linkStatuses := validator.ValidateLinksAsync(filteredLinks, "", config.Timeout, config.Workers)
-
 	var results []Result
-	for _, status := range linkStatuses {
-		result := Result{
-			URL:    status.Link,
-			Source: inputURL,
-		}
+	progressDisplay.queue(len(filteredLinks))
+	validator.ValidateLinksWithOptions(filteredLinks, validator.Options{
+		Timeout: config.Timeout,
+		Workers: config.Workers,
+		Logger:  logger,
+		OnStart: progressDisplay.begin,
+		OnResult: func(status validator.LinkStatus) {
+			progressDisplay.finish(status.Link)
+			result := Result{URL: status.Link, Source: inputURL}
+			applyStatus(&result, status)
+			reportResult(result)
+			results = append(results, result)
+		},
+	})
 
-		if status.Valid {
-			result.Status = "valid"
-			result.StatusCode = status.StatusCode
-		} else {
-			result.Status = "invalid"
-			result.Error = status.Reason
-			result.StatusCode = status.StatusCode
-		}
+	return results, nil
+}
 
-		results = append(results, result)
+// applyStatus fills in the outcome of a completed check
+func applyStatus(result *Result, status validator.LinkStatus) {
+	result.StatusCode = status.StatusCode
+	result.Duration = status.Duration.String()
+	result.RedirectURL = status.RedirectURL
+	result.RedirectCode = status.RedirectCode
+	if status.Valid {
+		result.Status = "valid"
+	} else {
+		result.Status = "invalid"
+		result.Error = status.Reason
+		result.ErrorKind = string(status.ErrorKind)
 	}
+}
 
-	return results, nil
+// skipReason returns why a link is left unchecked by --offline or --external-only,
+// or "" if it is checked
+func skipReason(link string) string {
+	switch {
+	case config.Offline && isURL(link):
+		return "offline"
+	case config.ExternalOnly && !isURL(link):
+		return "external links only"
+	}
+	return ""
+}
+
+// reportResult passes a result to the streaming output, if any, as soon as it is known
+func reportResult(result Result) {
+	if resultReporter == nil || (config.OnlyDead && result.Status != "invalid") {
+		return
+	}
+	if result.Status == "invalid" && streamBaseline[baselineEntryFor(result).key()] {
+		result.Baselined = true
+	}
+	resultReporter(result)
+}
+
+// hasUncheckableScheme reports whether a link uses a scheme like mailto: or
+// javascript: that can neither be fetched nor resolved on disk
+func hasUncheckableScheme(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	// Single letters are Windows drive letters, not schemes
+	if len(u.Scheme) == 1 {
+		return false
+	}
+	return u.Scheme != "http" && u.Scheme != "https"
 }
 
 func isMarkdownFile(path string) bool {
@@ -469,61 +1048,273 @@ func isMarkdownFile(path string) bool {
 	return ext == ".md" || ext == ".markdown"
 }
 
-func outputJSON(output Output) error {
-	encoder := json.NewEncoder(os.Stdout)
+func isHTMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".html" || ext == ".htm"
+}
+
+func isSupportedFile(path string) bool {
+	return isMarkdownFile(path) || isHTMLFile(path)
+}
+
+// outputFormatter renders the final output in one format
+type outputFormatter func(w io.Writer, output Output) error
+
+// outputFormatters maps each --format value to its renderer
+var outputFormatters = map[string]outputFormatter{
+	"text":               outputText,
+	"json":               outputJSON,
+	"sarif":              outputSARIF,
+	"junit":              outputJUnit,
+	"markdown":           outputMarkdown,
+	"ndjson":             outputNDJSON,
+	"csv":                outputCSV,
+	"tsv":                outputTSV,
+	"template":           outputTemplate,
+	"html":               outputHTML,
+	"github":             outputGitHub,
+	"gitlab-codequality": outputGitLabCodeQuality,
+}
+
+// fileFormatters replace outputFormatters for --output files where the terminal
+// output depends on terminal settings
+var fileFormatters = map[string]func(w io.Writer, output Output) error{
+	"text": outputTextFile,
+}
+
+// resultStream writes results while the check is still running
+type resultStream interface {
+	// Result writes a single result as soon as it is known
+	Result(result Result)
+	// Finish writes the lint findings and the summary once the run is complete and
+	// reports any write error
+	Finish(findings []Finding, duration string) error
+}
+
+// streamingFormatters are formats that write each result as soon as it is checked
+// instead of buffering the whole output
+var streamingFormatters = map[string]func(w io.Writer) resultStream{
+	"ndjson": newNDJSONStream,
+}
+
+// logger writes diagnostics to stderr, configured by --log-level and --log-format
+var logger = slog.Default()
+
+// newLogger creates the logger for the configured level and format
+func newLogger(w io.Writer) (*slog.Logger, error) {
+	level := config.LogLevel
+	if config.Debug {
+		level = "debug"
+	}
+	var opts slog.HandlerOptions
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level '%s': must be 'debug', 'info', 'warn' or 'error'", level)
+	}
+	opts.Level = l
+
+	switch config.LogFormat {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, &opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, &opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format '%s': must be 'text' or 'json'", config.LogFormat)
+	}
+}
+
+// progressDisplay shows the live progress line; it is nil when not shown
+var progressDisplay *progress
+
+// stopProgress clears the progress line before anything else is printed
+func stopProgress() {
+	progressDisplay.stop()
+	progressDisplay = nil
+}
+
+// resultReporter receives results as they complete; it is nil unless a streaming format is used
+var resultReporter func(Result)
+
+// streamBaseline holds the keys of the baseline entries while results are streamed
+var streamBaseline map[string]bool
+
+// streamOnly is set when results are streamed and no report file needs all of them
+var streamOnly bool
+
+// formatNames returns the supported output formats in alphabetical order
+func formatNames() []string {
+	names := make([]string, 0, len(outputFormatters))
+	for name := range outputFormatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func outputJSON(w io.Writer, output Output) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
 }
 
-func outputText(output Output) error {
-	fmt.Printf("Link Check Results\n")
-	fmt.Printf("==================\n\n")
+// textOptions are the settings of one text report, since a report file is
+// written differently than the terminal output
+type textOptions struct {
+	quiet   bool // list broken links only
+	verbose bool // show redirects and durations
+	color   bool
+}
 
-	// Group results by source
-	sourceGroups := make(map[string][]Result)
-	for _, result := range output.Results {
-		sourceGroups[result.Source] = append(sourceGroups[result.Source], result)
-	}
+// outputText writes the text report to stdout or watch mode's terminal, following
+// --quiet, --verbose and --color
+func outputText(w io.Writer, output Output) error {
+	return writeTextReport(w, output, textOptions{
+		quiet:   config.Quiet,
+		verbose: config.Verbose,
+		color:   colorEnabled(config.Color, w),
+	})
+}
 
-	for source, results := range sourceGroups {
-		if isURL(source) {
-			fmt.Printf("🌐 Checking web page: %s\n", source)
-		} else {
-			fmt.Printf("📄 Checking file: %s\n", source)
+// outputTextFile writes the text report to an --output file. It lists every
+// result without color, since --quiet and --color are meant for the terminal.
+func outputTextFile(w io.Writer, output Output) error {
+	return writeTextReport(w, output, textOptions{verbose: config.Verbose})
+}
+
+func writeTextReport(w io.Writer, output Output, opts textOptions) error {
+	colors := palette(opts.color)
+
+	fmt.Fprintf(w, "%s\n", colors.bold("Link Check Results"))
+	fmt.Fprintf(w, "==================\n\n")
+
+	for _, group := range groupResults(config.GroupBy, output.Results) {
+		results := group.Results
+		// Quiet mode lists broken links only
+		if opts.quiet {
+			results = nil
+			for _, result := range group.Results {
+				if result.Status == "invalid" {
+					results = append(results, result)
+				}
+			}
+			if len(results) == 0 {
+				continue
+			}
 		}
-		fmt.Println(strings.Repeat("-", len(source)+20))
+
+		fmt.Fprintln(w, colors.bold(textGroupHeading(config.GroupBy, group.Name)))
+		fmt.Fprintln(w, strings.Repeat("-", len(group.Name)+20))
 
 		for _, result := range results {
-			status := "✓"
-			if result.Status == "invalid" {
-				status = "✗"
+			status := colors.green("✓")
+			switch result.Status {
+			case "invalid":
+				status = colors.red("✗")
+			case "suppressed":
+				status = colors.yellow("⊘")
+			case "skipped":
+				status = colors.dim("-")
 			}
 
-			fmt.Printf("%s %s\n", status, result.URL)
+			fmt.Fprintf(w, "%s %s\n", status, result.URL)
+			if config.GroupBy != "" && config.GroupBy != "source" {
+				fmt.Fprintf(w, "  Source: %s\n", result.Source)
+			}
 			if result.Line > 0 {
-				fmt.Printf("  Line: %d\n", result.Line)
+				fmt.Fprintf(w, "  Line: %d\n", result.Line)
 			}
 
 			if result.StatusCode > 0 {
-				fmt.Printf("  Status: %d\n", result.StatusCode)
+				fmt.Fprintf(w, "  Status: %d\n", result.StatusCode)
 			}
 			if result.Error != "" {
-				fmt.Printf("  Error: %s\n", result.Error)
+				fmt.Fprintf(w, "  Error: %s\n", colors.red(result.Error))
+			}
+			if result.Baselined {
+				fmt.Fprintf(w, "  %s\n", colors.yellow("Known from baseline"))
+			}
+			if result.Status == "suppressed" {
+				if result.Reason != "" {
+					fmt.Fprintf(w, "  Suppressed: %s\n", colors.yellow(result.Reason))
+				} else {
+					fmt.Fprintf(w, "  %s\n", colors.yellow("Suppressed"))
+				}
+			}
+			if result.Status == "skipped" {
+				fmt.Fprintf(w, "  Skipped: %s\n", colors.dim(result.Reason))
+			}
+			if opts.verbose {
+				if result.RedirectURL != "" {
+					fmt.Fprintf(w, "  Redirect: %s %s\n", colors.dim(fmt.Sprintf("(%d)", result.RedirectCode)), result.RedirectURL)
+				}
+				if result.Duration != "" {
+					fmt.Fprintf(w, "  Duration: %s\n", colors.dim(result.Duration))
+				}
 			}
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
-	fmt.Printf("Summary:\n")
-	fmt.Printf("  Total Links: %d\n", output.Summary.Total)
-	fmt.Printf("  Valid: %d\n", output.Summary.Valid)
-	fmt.Printf("  Invalid: %d\n", output.Summary.Invalid)
-	fmt.Printf("  Duration: %s\n", output.Summary.Duration)
+	if len(output.Findings) > 0 {
+		fmt.Fprintf(w, "%s\n", colors.bold("Lint Findings:"))
+		for _, finding := range output.Findings {
+			fmt.Fprintf(w, "  %s:%d:%d %s: %s\n", finding.Source, finding.Line, finding.Column, colors.yellow(finding.Rule), finding.Message)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(output.Stale) > 0 {
+		fmt.Fprintf(w, "%s\n", colors.bold("Stale Baseline Entries:"))
+		for _, entry := range output.Stale {
+			fmt.Fprintf(w, "  %s %s %s\n", colors.green("✓"), entry.Source, entry.URL)
+		}
+		fmt.Fprintf(w, "  These links are no longer broken; recreate the baseline to remove them.\n\n")
+	}
+
+	invalid := fmt.Sprint(output.Summary.Invalid)
+	if output.Summary.Invalid > 0 {
+		invalid = colors.red(invalid)
+	}
+	fmt.Fprintf(w, "%s\n", colors.bold("Summary:"))
+	fmt.Fprintf(w, "  Total Links: %d\n", output.Summary.Total)
+	fmt.Fprintf(w, "  Valid: %s\n", colors.green(fmt.Sprint(output.Summary.Valid)))
+	fmt.Fprintf(w, "  Invalid: %s\n", invalid)
+	if output.Summary.Suppressed > 0 {
+		fmt.Fprintf(w, "  Suppressed: %s\n", colors.yellow(fmt.Sprint(output.Summary.Suppressed)))
+	}
+	if output.Summary.Skipped > 0 {
+		fmt.Fprintf(w, "  Skipped: %s\n", colors.dim(fmt.Sprint(output.Summary.Skipped)))
+	}
+	if output.Summary.Baselined > 0 || len(output.Stale) > 0 {
+		fmt.Fprintf(w, "  Baselined: %d\n", output.Summary.Baselined)
+		fmt.Fprintf(w, "  New: %d\n", output.Summary.Invalid-output.Summary.Baselined)
+		fmt.Fprintf(w, "  Stale Baseline Entries: %d\n", len(output.Stale))
+	}
+	if len(output.Findings) > 0 {
+		fmt.Fprintf(w, "  Lint Findings: %d\n", len(output.Findings))
+	}
+	fmt.Fprintf(w, "  Duration: %s\n", output.Summary.Duration)
 
 	return nil
 }
 
+// textGroupHeading returns the heading printed above a group of results
+func textGroupHeading(groupBy, name string) string {
+	switch groupBy {
+	case "domain":
+		return "🔗 Domain: " + name
+	case "status":
+		return "Status: " + name
+	case "error":
+		return "Error: " + name
+	}
+	if isURL(name) {
+		return "🌐 Checking web page: " + name
+	}
+	return "📄 Checking file: " + name
+}
+
 func getHelpTemplate() string {
 	return `{{.Short}}
 
@@ -568,12 +1359,17 @@ func GetConfig() Config {
 
 // IsURLIgnored checks if a URL should be ignored based on the ignore patterns
 func IsURLIgnored(url string) bool {
-	for _, regex := range config.IgnoreRegex {
+	return ignorePatternFor(url) != ""
+}
+
+// ignorePatternFor returns the first --ignore pattern that matches a URL, or ""
+func ignorePatternFor(url string) string {
+	for i, regex := range config.IgnoreRegex {
 		if regex.MatchString(url) {
-			return true
+			return config.IgnoreList[i]
 		}
 	}
-	return false
+	return ""
 }
 
 // SetVersionInfo sets the version information for the CLI
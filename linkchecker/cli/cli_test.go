@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+)
+
+func TestParseOutputTargets(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	tests := []struct {
+		name    string
+		format  string
+		outputs []string
+		stdout  string
+		wantErr bool
+	}{
+		{name: "files", format: "text", outputs: []string{"json=out/r.json", "sarif=r.sarif"}, stdout: "text"},
+		{name: "stdout", format: "text", outputs: []string{"ndjson=-", "html=r.html"}, stdout: "ndjson"},
+		{name: "missing path", format: "text", outputs: []string{"json"}, wantErr: true},
+		{name: "unknown format", format: "text", outputs: []string{"yaml=r.yaml"}, wantErr: true},
+		{name: "two on stdout", format: "text", outputs: []string{"json=-", "csv=-"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Format, config.OutputList = tt.format, tt.outputs
+			err := parseOutputTargets()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOutputTargets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && stdoutFormat() != tt.stdout {
+				t.Errorf("stdoutFormat() = %s, want %s", stdoutFormat(), tt.stdout)
+			}
+		})
+	}
+}
+
+func TestWriteOutputTargets(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	dir := t.TempDir()
+	config.Outputs = []OutputTarget{
+		{Format: "json", Path: filepath.Join(dir, "reports", "links.json")},
+		{Format: "csv", Path: filepath.Join(dir, "links.csv")},
+	}
+	output := Output{Results: []Result{{URL: "https://ok.example", Status: "valid", Source: "README.md"}}}
+	output.Summary.Total, output.Summary.Valid = 1, 1
+
+	if err := writeOutputTargets(output); err != nil {
+		t.Fatalf("writeOutputTargets error: %v", err)
+	}
+
+	data, err := os.ReadFile(config.Outputs[0].Path)
+	if err != nil {
+		t.Fatalf("JSON report not written: %v", err)
+	}
+	var decoded Output
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Summary.Total != 1 {
+		t.Errorf("unexpected JSON report (%v): %s", err, data)
+	}
+	if _, err := os.Stat(config.Outputs[1].Path); err != nil {
+		t.Errorf("CSV report not written: %v", err)
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	var buf bytes.Buffer
+	config = Config{LogLevel: "info", LogFormat: "json"}
+	l, err := newLogger(&buf)
+	if err != nil {
+		t.Fatalf("newLogger error: %v", err)
+	}
+	l.Debug("hidden")
+	l.Info("shown", "url", "https://example.com")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON entry: %v\n%s", err, buf.String())
+	}
+	if entry["msg"] != "shown" || entry["url"] != "https://example.com" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+
+	config = Config{LogLevel: "warn", LogFormat: "text", Debug: true}
+	if l, err := newLogger(&buf); err != nil || !l.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("--debug should enable debug logging (err: %v)", err)
+	}
+
+	config = Config{LogLevel: "loud", LogFormat: "text"}
+	if _, err := newLogger(&buf); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	config = Config{LogLevel: "info", LogFormat: "xml"}
+	if _, err := newLogger(&buf); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestCheckLinks_Offline(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer func(saved http.RoundTripper) { http.DefaultTransport = saved }(http.DefaultTransport)
+	http.DefaultTransport = server.Client().Transport
+	cdn := "//" + strings.TrimPrefix(server.URL, "https://") + "/lib.js"
+
+	dir := t.TempDir()
+	doc := "# Title\n\n[home](" + server.URL + ") [cdn](" + cdn + ") [install](guide.md#install) [typo](guide.md#instal) [top](#title)\n"
+	for name, content := range map[string]string{"doc.md": doc, "guide.md": "## Install\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	docPath := filepath.Join(dir, "doc.md")
+
+	statuses := func() map[string]string {
+		t.Helper()
+		output, err := checkLinks()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]string)
+		for _, result := range output.Results {
+			got[result.URL] = result.Status
+		}
+		return got
+	}
+
+	config = Config{InputPaths: []string{docPath}, Timeout: time.Second, Workers: 2, Dialect: "commonmark", Offline: true}
+	want := map[string]string{
+		server.URL:         "skipped",
+		cdn:                "skipped",
+		"guide.md#install": "valid",
+		"guide.md#instal":  "invalid",
+		"#title":           "valid",
+	}
+	if got := statuses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected offline results:\n got: %v\nwant: %v", got, want)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("expected no requests offline, got %d", requests.Load())
+	}
+
+	config.Offline, config.ExternalOnly = false, true
+	want = map[string]string{
+		server.URL:         "valid",
+		cdn:                "valid",
+		"guide.md#install": "skipped",
+		"guide.md#instal":  "skipped",
+		"#title":           "skipped",
+	}
+	if got := statuses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected external-only results:\n got: %v\nwant: %v", got, want)
+	}
+
+	// A skipped link cannot be judged, so its baseline entry is not stale
+	output := Output{Results: []Result{{URL: server.URL, Status: "skipped", Source: displaySource(docPath)}}}
+	applyBaseline(&output, Baseline{Entries: []BaselineEntry{{Source: displaySource(docPath), URL: server.URL}}})
+	summarize(&output, time.Now())
+	if len(output.Stale) != 0 || output.Summary.Skipped != 1 || output.Summary.Invalid != 0 {
+		t.Errorf("unexpected output for skipped link: %+v", output)
+	}
+
+	defer func(saved *slog.Logger) { logger = saved }(logger)
+	config.LogLevel, config.LogFormat, config.Offline = "warn", "text", true
+	if err := prepareCheck([]string{docPath}); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("expected --offline and --external-only to be rejected together, got %v", err)
+	}
+	config.ExternalOnly = false
+	if err := prepareCheck([]string{server.URL}); err == nil || !strings.Contains(err.Error(), "cannot check URLs") {
+		t.Errorf("expected --offline to reject URL inputs, got %v", err)
+	}
+}
+
+func TestCheckLinks_AnchorMismatch(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	// Anchors are checked in every run. Fragments are case-sensitive like in a
+	// browser, and {#id} heading attributes are heading text, not custom IDs.
+	dir := t.TempDir()
+	writeTestFile(t, dir, "guide.md", "## Install\n\n## Setup {#setup}\n")
+	docPath := writeTestFile(t, dir, "doc.md",
+		"[a](guide.md#install) [b](guide.md#Install) [c](guide.md#setup) [d](guide.md#setup-setup)\n")
+	config = Config{InputPaths: []string{docPath}, Timeout: time.Second, Workers: 2, Dialect: "commonmark"}
+
+	output, err := checkLinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, result := range output.Results {
+		got[result.URL] = result.Status
+		if result.Status == "invalid" && result.ErrorKind != string(validator.ErrorAnchorNotFound) {
+			t.Errorf("%s: expected error kind %s, got %s", result.URL, validator.ErrorAnchorNotFound, result.ErrorKind)
+		}
+	}
+	want := map[string]string{
+		"guide.md#install":     "valid",
+		"guide.md#Install":     "invalid",
+		"guide.md#setup":       "invalid",
+		"guide.md#setup-setup": "valid",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected results:\n got: %v\nwant: %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// csvHeader lists the columns of CSV and TSV output; keep the order stable,
+// spreadsheets and scripts address columns by position
+var csvHeader = []string{"source", "line", "url", "status", "status_code", "error_kind", "error", "duration"}
+
+func outputCSV(w io.Writer, output Output) error {
+	return writeDelimited(w, output, ',')
+}
+
+func outputTSV(w io.Writer, output Output) error {
+	return writeDelimited(w, output, '\t')
+}
+
+// writeDelimited writes one row per result. Fields containing the delimiter,
+// quotes or line breaks are quoted.
+func writeDelimited(w io.Writer, output Output, delimiter rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, result := range output.Results {
+		record := []string{
+			result.Source,
+			optionalInt(result.Line),
+			result.URL,
+			result.Status,
+			optionalInt(result.StatusCode),
+			result.ErrorKind,
+			result.Error,
+			result.Duration,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// optionalInt leaves unknown values such as a missing line number empty
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
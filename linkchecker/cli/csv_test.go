@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestOutputCSV(t *testing.T) {
+	output := Output{Results: []Result{
+		{URL: "https://ok.example", Status: "valid", StatusCode: 200, Source: "README.md", Line: 3, Duration: "120ms"},
+		{URL: "./missing.md", Status: "invalid", Error: "stat missing.md: no such file, really", ErrorKind: "file-not-found", Source: "README.md"},
+	}}
+
+	for _, tt := range []struct {
+		name      string
+		format    outputFormatter
+		delimiter rune
+	}{
+		{"csv", outputCSV, ','},
+		{"tsv", outputTSV, '\t'},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.format(&buf, output); err != nil {
+				t.Fatalf("output error: %v", err)
+			}
+
+			reader := csv.NewReader(&buf)
+			reader.Comma = tt.delimiter
+			records, err := reader.ReadAll()
+			if err != nil {
+				t.Fatalf("unreadable output: %v", err)
+			}
+			want := [][]string{
+				csvHeader,
+				{"README.md", "3", "https://ok.example", "valid", "200", "", "", "120ms"},
+				{"README.md", "", "./missing.md", "invalid", "", "file-not-found", "stat missing.md: no such file, really", ""},
+			}
+			if !reflect.DeepEqual(records, want) {
+				t.Errorf("unexpected records:\n got: %q\nwant: %q", records, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// reportDiff compares two JSON reports link by link. Links are matched by source
+// and URL; a link counts as broken if any of its occurrences is broken.
+type reportDiff struct {
+	Summary struct {
+		NewlyBroken int `json:"newly_broken"`
+		Fixed       int `json:"fixed"`
+		StillBroken int `json:"still_broken"`
+		Unchecked   int `json:"unchecked"`
+		Added       int `json:"added"`
+		Removed     int `json:"removed"`
+	} `json:"summary"`
+	NewlyBroken []diffEntry `json:"newly_broken"`
+	Fixed       []diffEntry `json:"fixed"`
+	StillBroken []diffEntry `json:"still_broken"`
+	// Unchecked are links that were broken and are now suppressed or skipped, so
+	// it is unknown whether they were fixed
+	Unchecked []diffEntry `json:"unchecked"`
+	Added     []diffEntry `json:"added"`
+	Removed   []diffEntry `json:"removed"`
+}
+
+// diffEntry is a link with its status in the old and the new report
+type diffEntry struct {
+	Source    string `json:"source"`
+	URL       string `json:"url"`
+	Line      int    `json:"line,omitempty"`
+	OldStatus string `json:"old_status,omitempty"`
+	NewStatus string `json:"new_status,omitempty"`
+	ErrorKind string `json:"error_kind,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// diffLink aggregates all occurrences of a URL in one source
+type diffLink struct {
+	result Result
+	broken bool
+	// checked is false if every occurrence was suppressed or skipped
+	checked bool
+}
+
+// newBreakage counts links that are broken now but weren't before, including new links
+func (d reportDiff) newBreakage() int {
+	count := len(d.NewlyBroken)
+	for _, entry := range d.Added {
+		if entry.NewStatus == "invalid" {
+			count++
+		}
+	}
+	return count
+}
+
+func diffLinks(results []Result) (map[string]diffLink, []string) {
+	links := make(map[string]diffLink)
+	var keys []string
+	for _, result := range results {
+		key := result.Source + "\x00" + result.URL
+		link, seen := links[key]
+		if !seen {
+			keys = append(keys, key)
+			link.result = result
+		}
+		// The first broken occurrence represents the link
+		if result.Status == "invalid" && !link.broken {
+			link.result, link.broken = result, true
+		}
+		if result.Status == "valid" || result.Status == "invalid" {
+			link.checked = true
+		}
+		links[key] = link
+	}
+	return links, keys
+}
+
+func diffReports(before, after Output) reportDiff {
+	oldLinks, oldKeys := diffLinks(before.Results)
+	newLinks, newKeys := diffLinks(after.Results)
+
+	d := reportDiff{
+		NewlyBroken: []diffEntry{},
+		Fixed:       []diffEntry{},
+		StillBroken: []diffEntry{},
+		Unchecked:   []diffEntry{},
+		Added:       []diffEntry{},
+		Removed:     []diffEntry{},
+	}
+	for _, key := range newKeys {
+		link := newLinks[key]
+		entry := diffEntry{
+			Source:    link.result.Source,
+			URL:       link.result.URL,
+			Line:      link.result.Line,
+			NewStatus: link.result.Status,
+			ErrorKind: link.result.ErrorKind,
+			Error:     link.result.Error,
+		}
+		old, existed := oldLinks[key]
+		if existed {
+			entry.OldStatus = old.result.Status
+		}
+		switch {
+		case !existed:
+			d.Added = append(d.Added, entry)
+		case link.broken && old.broken:
+			d.StillBroken = append(d.StillBroken, entry)
+		case link.broken:
+			d.NewlyBroken = append(d.NewlyBroken, entry)
+		case !link.checked && old.broken:
+			// Show why it used to fail
+			entry.ErrorKind, entry.Error = old.result.ErrorKind, old.result.Error
+			d.Unchecked = append(d.Unchecked, entry)
+		case old.broken:
+			// Show why it used to fail
+			entry.ErrorKind, entry.Error = old.result.ErrorKind, old.result.Error
+			d.Fixed = append(d.Fixed, entry)
+		}
+	}
+	for _, key := range oldKeys {
+		if _, exists := newLinks[key]; !exists {
+			old := oldLinks[key].result
+			d.Removed = append(d.Removed, diffEntry{
+				Source:    old.Source,
+				URL:       old.URL,
+				Line:      old.Line,
+				OldStatus: old.Status,
+				ErrorKind: old.ErrorKind,
+				Error:     old.Error,
+			})
+		}
+	}
+
+	for _, entries := range [][]diffEntry{d.NewlyBroken, d.Fixed, d.StillBroken, d.Unchecked, d.Added, d.Removed} {
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].Source != entries[j].Source {
+				return entries[i].Source < entries[j].Source
+			}
+			if entries[i].Line != entries[j].Line {
+				return entries[i].Line < entries[j].Line
+			}
+			return entries[i].URL < entries[j].URL
+		})
+	}
+
+	d.Summary.NewlyBroken = len(d.NewlyBroken)
+	d.Summary.Fixed = len(d.Fixed)
+	d.Summary.StillBroken = len(d.StillBroken)
+	d.Summary.Unchecked = len(d.Unchecked)
+	d.Summary.Added = len(d.Added)
+	d.Summary.Removed = len(d.Removed)
+	return d
+}
+
+func outputDiffText(w io.Writer, d reportDiff) error {
+	colors := palette(colorEnabled(config.Color, w))
+
+	sections := []struct {
+		title   string
+		marker  string
+		entries []diffEntry
+	}{
+		{"Newly Broken", colors.red("✗"), d.NewlyBroken},
+		{"Fixed", colors.green("✓"), d.Fixed},
+		{"Still Broken", colors.yellow("✗"), d.StillBroken},
+		{"Unchecked", colors.dim("?"), d.Unchecked},
+		{"Added", colors.cyan("+"), d.Added},
+		{"Removed", colors.dim("-"), d.Removed},
+	}
+
+	fmt.Fprintf(w, "%s\n", colors.bold("Link Check Diff"))
+	fmt.Fprintf(w, "===============\n\n")
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%d)\n", colors.bold(section.title), len(section.entries))
+		for _, entry := range section.entries {
+			location := entry.Source
+			if entry.Line > 0 {
+				location += fmt.Sprintf(":%d", entry.Line)
+			}
+			fmt.Fprintf(w, "  %s %s %s\n", section.marker, entry.URL, colors.dim(location))
+			if entry.Error != "" {
+				fmt.Fprintf(w, "      %s\n", entry.Error)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%s\n", colors.bold("Summary:"))
+	fmt.Fprintf(w, "  Newly Broken: %d\n", d.Summary.NewlyBroken)
+	fmt.Fprintf(w, "  Fixed: %d\n", d.Summary.Fixed)
+	fmt.Fprintf(w, "  Still Broken: %d\n", d.Summary.StillBroken)
+	if d.Summary.Unchecked > 0 {
+		fmt.Fprintf(w, "  Unchecked: %d\n", d.Summary.Unchecked)
+	}
+	fmt.Fprintf(w, "  Added: %d\n", d.Summary.Added)
+	fmt.Fprintf(w, "  Removed: %d\n", d.Summary.Removed)
+	return nil
+}
+
+func outputDiffJSON(w io.Writer, d reportDiff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// loadReport reads a report written with --format=json
+func loadReport(path string) (Output, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Output{}, fmt.Errorf("error reading report: %w", err)
+	}
+	var output Output
+	if err := json.Unmarshal(data, &output); err != nil {
+		return Output{}, fmt.Errorf("error parsing report %s: %w", path, err)
+	}
+	return output, nil
+}
+
+func newDiffCommand() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "diff old.json new.json",
+		Short: "Compare two JSON reports and list newly broken and fixed links",
+		Long: `Compare two reports written with --format=json. Links are matched by source
+and URL and listed as newly broken, fixed, still broken, added or removed.
+The command fails when links broke, including newly added broken links.`,
+		Example: `  linkchecker --format=json ./docs > old.json
+  linkchecker --format=json ./docs > new.json
+  linkchecker diff old.json new.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", format)
+			}
+			if err := validateColor(config.Color); err != nil {
+				return err
+			}
+
+			before, err := loadReport(args[0])
+			if err != nil {
+				return err
+			}
+			after, err := loadReport(args[1])
+			if err != nil {
+				return err
+			}
+
+			d := diffReports(before, after)
+			if format == "json" {
+				err = outputDiffJSON(os.Stdout, d)
+			} else {
+				err = outputDiffText(os.Stdout, d)
+			}
+			if err != nil {
+				return err
+			}
+			if broken := d.newBreakage(); broken > 0 {
+				return fmt.Errorf("%d links broke since %s", broken, args[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: 'text' or 'json'")
+	cmd.Flags().StringVar(&config.Color, "color", "auto",
+		"Color terminal output: 'auto' (terminals only, honors NO_COLOR), 'always' or 'never'")
+	return cmd
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffReports(t *testing.T) {
+	before := Output{Results: []Result{
+		{URL: "https://broke.example", Status: "valid", Source: "a.md", Line: 1},
+		{URL: "https://fixed.example", Status: "invalid", Error: "404 Not Found", ErrorKind: "not-found", Source: "a.md", Line: 2},
+		{URL: "https://still.example", Status: "invalid", Error: "timeout", ErrorKind: "timeout", Source: "a.md", Line: 3},
+		{URL: "https://muted.example", Status: "invalid", Error: "500", ErrorKind: "http-server-error", Source: "a.md", Line: 4},
+		{URL: "https://offline.example", Status: "invalid", Error: "dns", ErrorKind: "dns", Source: "a.md", Line: 5},
+		{URL: "https://gone.example", Status: "valid", Source: "b.md", Line: 1},
+		{URL: "https://ok.example", Status: "valid", Source: "a.md", Line: 6},
+	}}
+	after := Output{Results: []Result{
+		{URL: "https://broke.example", Status: "invalid", Error: "404 Not Found", ErrorKind: "not-found", Source: "a.md", Line: 1},
+		{URL: "https://fixed.example", Status: "valid", Source: "a.md", Line: 2},
+		// One broken occurrence makes the link broken
+		{URL: "https://still.example", Status: "valid", Source: "a.md", Line: 3},
+		{URL: "https://still.example", Status: "invalid", Error: "timeout", ErrorKind: "timeout", Source: "a.md", Line: 9},
+		{URL: "https://muted.example", Status: "suppressed", Reason: "flaky", Source: "a.md", Line: 4},
+		{URL: "https://offline.example", Status: "skipped", Reason: "offline", Source: "a.md", Line: 5},
+		{URL: "https://ok.example", Status: "valid", Source: "a.md", Line: 6},
+		{URL: "https://new-ok.example", Status: "valid", Source: "c.md", Line: 1},
+		{URL: "https://new-broken.example", Status: "invalid", Error: "dns", ErrorKind: "dns", Source: "c.md", Line: 2},
+	}}
+
+	d := diffReports(before, after)
+	urls := func(entries []diffEntry) []string {
+		list := []string{}
+		for _, entry := range entries {
+			list = append(list, entry.URL)
+		}
+		return list
+	}
+	for name, got := range map[string][]string{
+		"newly broken": urls(d.NewlyBroken),
+		"fixed":        urls(d.Fixed),
+		"still broken": urls(d.StillBroken),
+		"unchecked":    urls(d.Unchecked),
+		"added":        urls(d.Added),
+		"removed":      urls(d.Removed),
+	} {
+		want := map[string][]string{
+			"newly broken": {"https://broke.example"},
+			"fixed":        {"https://fixed.example"},
+			"still broken": {"https://still.example"},
+			"unchecked":    {"https://muted.example", "https://offline.example"},
+			"added":        {"https://new-ok.example", "https://new-broken.example"},
+			"removed":      {"https://gone.example"},
+		}[name]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+
+	if d.StillBroken[0].Line != 9 || d.StillBroken[0].OldStatus != "invalid" || d.StillBroken[0].NewStatus != "invalid" {
+		t.Errorf("expected the broken occurrence to represent the link, got %+v", d.StillBroken[0])
+	}
+	if fixed := d.Fixed[0]; fixed.Error != "404 Not Found" || fixed.OldStatus != "invalid" || fixed.NewStatus != "valid" {
+		t.Errorf("expected a fixed link to show its old error, got %+v", fixed)
+	}
+	if unchecked := d.Unchecked[1]; unchecked.Error != "dns" || unchecked.NewStatus != "skipped" {
+		t.Errorf("expected an unchecked link to show its old error, got %+v", unchecked)
+	}
+
+	s := d.Summary
+	if s.NewlyBroken != 1 || s.Fixed != 1 || s.StillBroken != 1 || s.Unchecked != 2 || s.Added != 2 || s.Removed != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if got := d.newBreakage(); got != 2 {
+		t.Errorf("newBreakage() = %d, want 2 (one newly broken and one added broken link)", got)
+	}
+	if got := diffReports(before, before).newBreakage(); got != 0 {
+		t.Errorf("newBreakage() of identical reports = %d, want 0", got)
+	}
+}
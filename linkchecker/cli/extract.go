@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"bxfferoverflow.me/link-checker/linkchecker/parser"
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+	"github.com/spf13/cobra"
+)
+
+// extractHeader lists the CSV columns of extract; count is added with --unique
+var extractHeader = []string{"source", "line", "column", "kind", "url", "resolved", "host"}
+
+// extractedLink is a link found by extract
+type extractedLink struct {
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Kind   string `json:"kind"`
+	URL    string `json:"url"`
+	// Resolved is the absolute URL; local files are file:// URLs
+	Resolved string `json:"resolved"`
+	Host     string `json:"host,omitempty"`
+	// Count is the number of occurrences with --unique
+	Count int `json:"count,omitempty"`
+}
+
+// extractGroup holds the links to one host
+type extractGroup struct {
+	Host  string          `json:"host"`
+	Links []extractedLink `json:"links"`
+}
+
+func newExtractCommand() *cobra.Command {
+	var format string
+	var unique, byHost bool
+	cmd := &cobra.Command{
+		Use:   "extract [paths...]",
+		Short: "List the links of Markdown and HTML files without checking them",
+		Long: `List every link that a normal run would check, with its source, line, kind and
+absolute URL, without sending any requests. Files are selected and links are
+filtered with the same options as a normal run.`,
+		Example: `  linkchecker extract -r ./docs
+  linkchecker extract -r --unique --by-host ./docs
+  linkchecker extract -r --format=csv ./docs > links.csv`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" && format != "csv" {
+				return fmt.Errorf("invalid format '%s': must be 'text', 'json' or 'csv'", format)
+			}
+			if err := validateColor(config.Color); err != nil {
+				return err
+			}
+			if err := prepareCheck(args); err != nil {
+				return err
+			}
+			if len(config.InputURLs) > 0 {
+				return fmt.Errorf("extract only reads local files, not URLs")
+			}
+
+			links, err := extractLinks()
+			if err != nil {
+				return err
+			}
+			if unique {
+				links = uniqueLinks(links)
+			}
+			groups := []extractGroup{{Links: links}}
+			if byHost {
+				groups = groupByHost(links)
+			}
+
+			switch format {
+			case "json":
+				return outputExtractJSON(os.Stdout, groups, byHost)
+			case "csv":
+				return outputExtractCSV(os.Stdout, groups, unique)
+			default:
+				return outputExtractText(os.Stdout, groups, unique, byHost)
+			}
+		},
+	}
+	addCheckFlags(cmd)
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: 'text', 'json' or 'csv'")
+	cmd.Flags().BoolVar(&unique, "unique", false,
+		"List each resolved URL once with the number of occurrences")
+	cmd.Flags().BoolVar(&byHost, "by-host", false,
+		"Group links by host")
+	cmd.Flags().StringVar(&config.Color, "color", "auto",
+		"Color terminal output: 'auto' (terminals only, honors NO_COLOR), 'always' or 'never'")
+	return cmd
+}
+
+// extractLinks parses the input files and returns their links in source order
+func extractLinks() ([]extractedLink, error) {
+	links := []extractedLink{}
+	for _, inputPath := range config.InputPaths {
+		info, err := os.Stat(inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("path does not exist: %s", inputPath)
+		}
+		siteRoot := siteRootFor(inputPath, info)
+
+		err = walkFiles(inputPath, func(filePath string) error {
+			if !isSelected(filePath) {
+				return nil
+			}
+			doc, err := parseDocument(filePath)
+			if err != nil {
+				return fmt.Errorf("error extracting links from %s: %w", filePath, err)
+			}
+			for _, link := range doc.Links {
+				// The same links a normal run checks
+				if link.URL == "" || hasUncheckableScheme(link.URL) || IsURLIgnored(link.URL) || link.Suppressed {
+					continue
+				}
+				resolved := resolveLink(link, filePath, siteRoot)
+				extracted := extractedLink{
+					Source:   displaySource(filePath),
+					Line:     link.Line,
+					Column:   link.Column,
+					Kind:     string(link.Kind),
+					URL:      link.URL,
+					Resolved: resolved,
+				}
+				if u, err := url.Parse(resolved); err == nil {
+					extracted.Host = u.Host
+				}
+				links = append(links, extracted)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error processing path '%s': %w", inputPath, err)
+		}
+	}
+	return links, nil
+}
+
+// resolveLink returns the absolute URL of a link. Local links become file:// URLs
+// that keep their fragment.
+func resolveLink(link parser.Link, filePath, siteRoot string) string {
+	if strings.HasPrefix(link.URL, "//") {
+		// Protocol-relative links are fetched like on an HTTPS page
+		return "https:" + link.URL
+	}
+	if isURL(link.URL) {
+		return link.URL
+	}
+	opts := validator.Options{BasePath: filepath.Dir(filePath), SiteRoot: siteRoot}
+	target := validator.ResolvePath(link.URL, opts)
+	if target == "" {
+		// An anchor points into the file itself
+		target = filePath
+	}
+	if abs, err := filepath.Abs(target); err == nil {
+		target = abs
+	}
+	resolved := (&url.URL{Scheme: "file", Path: filepath.ToSlash(target)}).String()
+	if i := strings.Index(link.URL, "#"); i >= 0 {
+		resolved += link.URL[i:]
+	}
+	return resolved
+}
+
+// uniqueLinks keeps the first occurrence of each resolved URL and counts all of them
+func uniqueLinks(links []extractedLink) []extractedLink {
+	unique := []extractedLink{}
+	index := make(map[string]int)
+	for _, link := range links {
+		if i, ok := index[link.Resolved]; ok {
+			unique[i].Count++
+			continue
+		}
+		index[link.Resolved] = len(unique)
+		link.Count = 1
+		unique = append(unique, link)
+	}
+	return unique
+}
+
+// groupByHost groups links by host, sorted by host name; local links come first
+func groupByHost(links []extractedLink) []extractGroup {
+	byHost := make(map[string][]extractedLink)
+	for _, link := range links {
+		byHost[link.Host] = append(byHost[link.Host], link)
+	}
+	hosts := make([]string, 0, len(byHost))
+	for host := range byHost {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	groups := make([]extractGroup, 0, len(hosts))
+	for _, host := range hosts {
+		groups = append(groups, extractGroup{Host: host, Links: byHost[host]})
+	}
+	return groups
+}
+
+func outputExtractText(w io.Writer, groups []extractGroup, unique, byHost bool) error {
+	colors := palette(colorEnabled(config.Color, w))
+	total := 0
+	for i, group := range groups {
+		if byHost {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			host := group.Host
+			if host == "" {
+				host = "(local)"
+			}
+			fmt.Fprintln(w, colors.bold(fmt.Sprintf("%s (%d)", host, len(group.Links))))
+		}
+		var rows [][]string
+		for _, link := range group.Links {
+			location := fmt.Sprintf("%s:%d:%d", link.Source, link.Line, link.Column)
+			if unique {
+				rows = append(rows, []string{link.Resolved, link.Kind, fmt.Sprintf("%d× first at %s", link.Count, location)})
+			} else {
+				rows = append(rows, []string{location, link.Kind, link.Resolved})
+			}
+		}
+		styles := []func(string) string{nil, colors.dim, nil}
+		if unique {
+			styles[2] = colors.dim
+		}
+		if err := writeColumns(w, rows, styles); err != nil {
+			return err
+		}
+		total += len(group.Links)
+	}
+
+	noun := "links"
+	if unique {
+		noun = "unique links"
+	}
+	fmt.Fprintf(w, "\n%d %s\n", total, noun)
+	return nil
+}
+
+// writeColumns writes rows with their columns aligned like a tabwriter would, but
+// pads by the visible width of each cell, so that color codes added by the
+// styles do not shift the columns
+func writeColumns(w io.Writer, rows [][]string, styles []func(string) string) error {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row[:len(row)-1] {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			text := cell
+			if i < len(styles) && styles[i] != nil {
+				text = styles[i](cell)
+			}
+			b.WriteString(text)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func outputExtractJSON(w io.Writer, groups []extractGroup, byHost bool) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if byHost {
+		return encoder.Encode(groups)
+	}
+	return encoder.Encode(groups[0].Links)
+}
+
+func outputExtractCSV(w io.Writer, groups []extractGroup, unique bool) error {
+	writer := csv.NewWriter(w)
+	header := extractHeader
+	if unique {
+		header = append(append([]string{}, header...), "count")
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, group := range groups {
+		for _, link := range group.Links {
+			record := []string{
+				link.Source,
+				strconv.Itoa(link.Line),
+				strconv.Itoa(link.Column),
+				link.Kind,
+				link.URL,
+				link.Resolved,
+				link.Host,
+			}
+			if unique {
+				record = append(record, strconv.Itoa(link.Count))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
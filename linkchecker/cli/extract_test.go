@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	dir := t.TempDir()
+	md := "[a](https://example.com/a) ![img](img/logo.png) [b](https://other.test/b)\n" +
+		"[top](#intro) [again](https://example.com/a) [skip](https://ignored.test) [mail](mailto:x@example.com)\n"
+	if err := os.WriteFile(filepath.Join(dir, "doc.md"), []byte(md), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config = Config{InputPaths: []string{dir}, IgnoreList: []string{"ignored.test"}, Dialect: "commonmark", Color: "never"}
+	if err := compileIgnorePatterns(); err != nil {
+		t.Fatal(err)
+	}
+	links, err := extractLinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileURL := "file://" + filepath.ToSlash(abs)
+	want := []struct{ kind, resolved, host string }{
+		{"link", "https://example.com/a", "example.com"},
+		{"image", fileURL + "/img/logo.png", ""},
+		{"link", "https://other.test/b", "other.test"},
+		{"link", fileURL + "/doc.md#intro", ""},
+		{"link", "https://example.com/a", "example.com"},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("expected %d links, got %+v", len(want), links)
+	}
+	for i, w := range want {
+		if links[i].Kind != w.kind || links[i].Resolved != w.resolved || links[i].Host != w.host || links[i].Line == 0 {
+			t.Errorf("link %d: got %+v, want %+v", i, links[i], w)
+		}
+	}
+
+	unique := uniqueLinks(links)
+	if len(unique) != 4 || unique[0].Count != 2 || unique[1].Count != 1 {
+		t.Errorf("unexpected unique links: %+v", unique)
+	}
+
+	groups := groupByHost(unique)
+	if len(groups) != 3 || groups[0].Host != "" || groups[1].Host != "example.com" || groups[2].Host != "other.test" {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+
+	var buf bytes.Buffer
+	if err := outputExtractText(&buf, groups, true, true); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	for _, s := range []string{"(local) (2)\n", "example.com (1)\n", "https://example.com/a  link  2× first at ", "\n4 unique links\n"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("expected %q in:\n%s", s, text)
+		}
+	}
+
+	buf.Reset()
+	if err := outputExtractJSON(&buf, groups, true); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []extractGroup
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 3 || decoded[1].Links[0].Count != 2 {
+		t.Errorf("unexpected JSON (%v):\n%s", err, buf.String())
+	}
+
+	buf.Reset()
+	if err := outputExtractCSV(&buf, []extractGroup{{Links: links}}, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 || lines[0] != "source,line,column,kind,url,resolved,host" ||
+		!strings.HasSuffix(lines[1], ",link,https://example.com/a,https://example.com/a,example.com") {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestOutputExtractText_Color(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	groups := []extractGroup{{Links: []extractedLink{
+		{Source: "doc.md", Line: 1, Column: 4, Kind: "link", Resolved: "https://example.com/a"},
+		{Source: "doc.md", Line: 12, Column: 10, Kind: "image", Resolved: "file:///docs/logo.png"},
+	}}}
+	render := func(color string) string {
+		t.Helper()
+		config.Color = color
+		var buf bytes.Buffer
+		if err := outputExtractText(&buf, groups, false, false); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	plain, colored := render("never"), render("always")
+	if !strings.Contains(colored, ansiDim) {
+		t.Fatalf("expected colored output:\n%q", colored)
+	}
+	// Color codes must not change the alignment of the columns
+	stripped := strings.NewReplacer(ansiDim, "", ansiReset, "").Replace(colored)
+	if stripped != plain {
+		t.Errorf("colored columns are misaligned:\n%s\nwant:\n%s", stripped, plain)
+	}
+	if !strings.Contains(plain, "doc.md:1:4    link   https://example.com/a\n") {
+		t.Errorf("unexpected columns:\n%s", plain)
+	}
+}
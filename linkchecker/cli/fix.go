@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"bxfferoverflow.me/link-checker/linkchecker/parser"
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+	"github.com/spf13/cobra"
+)
+
+// diffContext is the number of unchanged lines around each change in a unified diff
+const diffContext = 3
+
+// linkEdit replaces the URL of one link in a source file
+type linkEdit struct {
+	Line int
+	// Start and End are the byte offsets of the old URL in the source
+	Start int
+	End   int
+	Old   string
+	New   string
+}
+
+// fileLinks are the links of one source file that may be rewritten
+type fileLinks struct {
+	path  string
+	links []parser.Link
+}
+
+func newFixCommand() *cobra.Command {
+	var dryRun bool
+	var hosts []string
+	cmd := &cobra.Command{
+		Use:   "fix [paths...]",
+		Short: "Rewrite permanently redirected links to their new location",
+		Long: `Check the links of Markdown and HTML files and replace every link that
+permanently redirects (301 or 308) with its final URL. Only the URL itself is
+changed, so the formatting of the files is preserved. Links that fail after
+the redirect are left alone. Without --allow-host, only redirects within the
+same host are rewritten.`,
+		Example: `  linkchecker fix --dry-run ./docs
+  linkchecker fix --allow-host=github.com --allow-host='*.example.com' ./docs`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := prepareCheck(args); err != nil {
+				return err
+			}
+			if len(config.InputURLs) > 0 {
+				return fmt.Errorf("fix only rewrites local files, not URLs")
+			}
+			for _, host := range hosts {
+				if _, err := path.Match(host, ""); err != nil {
+					return fmt.Errorf("invalid host pattern '%s': %w", host, err)
+				}
+			}
+			return runFix(os.Stdout, dryRun, hosts)
+		},
+	}
+	addCheckFlags(cmd)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Print a unified diff of the changes instead of writing them")
+	cmd.Flags().StringSliceVar(&hosts, "allow-host", []string{},
+		"Also rewrite redirects to other hosts if the old and new host are in this list (repeatable, '*.example.com' matches subdomains)")
+	return cmd
+}
+
+// runFix rewrites the permanently redirected links of all input paths
+func runFix(w io.Writer, dryRun bool, hosts []string) error {
+	files, err := collectFixableLinks()
+	if err != nil {
+		return err
+	}
+
+	// Each URL is checked once, no matter how often it is linked
+	var uniqueLinks []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		for _, link := range file.links {
+			if !seen[link.URL] {
+				seen[link.URL] = true
+				uniqueLinks = append(uniqueLinks, link.URL)
+			}
+		}
+	}
+	statuses := make(map[string]validator.LinkStatus)
+	startProgress(false)
+	progressDisplay.queue(len(uniqueLinks))
+	validator.ValidateLinksWithOptions(uniqueLinks, validator.Options{
+		Timeout: config.Timeout,
+		Workers: config.Workers,
+		Logger:  logger,
+		Cache:   resultCache,
+		OnStart: progressDisplay.begin,
+		OnResult: func(status validator.LinkStatus) {
+			progressDisplay.finish(status.Link)
+			statuses[status.Link] = status
+		},
+	})
+	stopProgress()
+
+	rewritten, changedFiles := 0, 0
+	for _, file := range files {
+		var edits []linkEdit
+		edited := make(map[int]bool)
+		for _, link := range file.links {
+			// Links through the same reference definition share its URL
+			if edit, ok := fixFor(link, statuses[link.URL], hosts); ok && !edited[edit.Start] {
+				edited[edit.Start] = true
+				edits = append(edits, edit)
+			}
+		}
+		if len(edits) == 0 {
+			continue
+		}
+
+		original, err := os.ReadFile(file.path)
+		if err != nil {
+			return err
+		}
+		fixed, edits := applyEdits(displaySource(file.path), original, edits)
+		if len(edits) == 0 {
+			continue
+		}
+		rewritten += len(edits)
+		changedFiles++
+
+		if dryRun {
+			fmt.Fprint(w, unifiedDiff(displaySource(file.path), original, fixed))
+			continue
+		}
+		info, err := os.Stat(file.path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file.path, fixed, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("error writing %s: %w", file.path, err)
+		}
+		for _, edit := range edits {
+			fmt.Fprintf(w, "%s:%d: %s → %s\n", displaySource(file.path), edit.Line, edit.Old, edit.New)
+		}
+	}
+
+	verb := "Rewrote"
+	if dryRun {
+		verb = "Would rewrite"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d links in %d files\n", verb, rewritten, changedFiles)
+	return nil
+}
+
+// collectFixableLinks parses the input files and keeps the external links that are
+// checked in a normal run and appear literally in their source
+func collectFixableLinks() ([]fileLinks, error) {
+	var files []fileLinks
+	for _, inputPath := range config.InputPaths {
+		err := walkFiles(inputPath, func(filePath string) error {
+			if !isSelected(filePath) {
+				return nil
+			}
+			doc, err := parseDocument(filePath)
+			if err != nil {
+				return fmt.Errorf("error extracting links from %s: %w", filePath, err)
+			}
+			src, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			file := fileLinks{path: filePath}
+			for _, link := range doc.Links {
+				if !isURL(link.URL) || hasUncheckableScheme(link.URL) || IsURLIgnored(link.URL) ||
+					link.Suppressed || !literalInSource(src, link) {
+					continue
+				}
+				file.links = append(file.links, link)
+			}
+			if len(file.links) > 0 {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error processing path '%s': %w", inputPath, err)
+		}
+	}
+	return files, nil
+}
+
+// fixFor returns the edit for a link that permanently redirects to a working URL
+func fixFor(link parser.Link, status validator.LinkStatus, hosts []string) (linkEdit, bool) {
+	if !status.Valid || !status.PermanentRedirect || status.RedirectURL == "" {
+		return linkEdit{}, false
+	}
+	// A final 3xx means the redirect limit was hit and the target still redirects
+	if status.StatusCode >= 300 && status.StatusCode < 400 {
+		return linkEdit{}, false
+	}
+	target := status.RedirectURL
+	// Links never move to an unexpected site: both hosts must be allowed, and
+	// without an allow-list the host must stay the same
+	oldHost, newHost := linkHostname(link.URL), linkHostname(target)
+	if len(hosts) == 0 && oldHost != newHost {
+		return linkEdit{}, false
+	}
+	if len(hosts) > 0 && (!hostAllowed(oldHost, hosts) || !hostAllowed(newHost, hosts)) {
+		return linkEdit{}, false
+	}
+
+	// Redirects usually drop the fragment, which still applies to the new page
+	if i := strings.Index(link.URL, "#"); i >= 0 && !strings.Contains(target, "#") {
+		target += link.URL[i:]
+	}
+	if target == link.URL {
+		return linkEdit{}, false
+	}
+	// Characters that would need escaping in Markdown or HTML are not written
+	if strings.ContainsAny(target, " \t\r\n\"'<>\\") || strings.Count(target, "(") != strings.Count(target, ")") {
+		logger.Warn("not rewriting link with unsafe characters", "url", link.URL, "target", target)
+		return linkEdit{}, false
+	}
+	return linkEdit{Line: link.Line, Start: link.Start, End: link.End, Old: link.URL, New: target}, true
+}
+
+// linkHostname returns the lower-case host name of a URL without port
+func linkHostname(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// literalInSource reports whether the offsets of a link hold its URL as written,
+// which is not the case for e.g. www. links that the parser prefixes with http://
+func literalInSource(src []byte, link parser.Link) bool {
+	return link.End > link.Start && link.End <= len(src) && string(src[link.Start:link.End]) == link.URL
+}
+
+// hostAllowed reports whether a host matches one of the allowed host patterns
+func hostAllowed(host string, hosts []string) bool {
+	for _, pattern := range hosts {
+		if ok, _ := path.Match(strings.ToLower(pattern), host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEdits replaces byte ranges of src and returns the edits it applied. A range
+// that no longer contains the old URL is skipped with a warning, so that one stale
+// link does not keep the rest of the file from being fixed.
+func applyEdits(source string, src []byte, edits []linkEdit) ([]byte, []linkEdit) {
+	sorted := append([]linkEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var buf bytes.Buffer
+	var applied []linkEdit
+	last := 0
+	for _, edit := range sorted {
+		if edit.Start < last || edit.End > len(src) || string(src[edit.Start:edit.End]) != edit.Old {
+			logger.Warn("not rewriting link", "source", source, "line", edit.Line, "url", edit.Old,
+				"reason", "the link is not written literally at its position")
+			continue
+		}
+		buf.Write(src[last:edit.Start])
+		buf.WriteString(edit.New)
+		last = edit.End
+		applied = append(applied, edit)
+	}
+	buf.Write(src[last:])
+	return buf.Bytes(), applied
+}
+
+// unifiedDiff renders the changes between two versions of a file. Edits never add
+// or remove lines, so changed lines are compared one by one.
+func unifiedDiff(name string, before, after []byte) string {
+	oldLines := strings.SplitAfter(string(before), "\n")
+	newLines := strings.SplitAfter(string(after), "\n")
+	if len(oldLines) != len(newLines) {
+		return ""
+	}
+
+	var changed []int
+	for i := range oldLines {
+		if oldLines[i] != newLines[i] {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+	for start := 0; start < len(changed); {
+		// Changes whose context overlaps share a hunk
+		end := start
+		for end+1 < len(changed) && changed[end+1]-changed[end] <= 2*diffContext {
+			end++
+		}
+		first := max(changed[start]-diffContext, 0)
+		last := min(changed[end]+diffContext, len(oldLines)-1)
+		if oldLines[last] == "" {
+			// The empty element after a trailing newline is not a line
+			last--
+		}
+		count := last - first + 1
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", first+1, count, first+1, count)
+		for i := first; i <= last; i++ {
+			if oldLines[i] == newLines[i] {
+				writeDiffLine(&b, " ", oldLines[i])
+			} else {
+				writeDiffLine(&b, "-", oldLines[i])
+				writeDiffLine(&b, "+", newLines[i])
+			}
+		}
+		start = end + 1
+	}
+	return b.String()
+}
+
+// writeDiffLine writes one line of a hunk, marking a missing newline at the end of the file
+func writeDiffLine(b *strings.Builder, prefix, line string) {
+	b.WriteString(prefix + line)
+	if !strings.HasSuffix(line, "\n") {
+		b.WriteString("\n\\ No newline at end of file\n")
+	}
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bxfferoverflow.me/link-checker/linkchecker/parser"
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+)
+
+func TestRunFix(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/ref":
+			http.Redirect(w, r, "/new-ref", http.StatusPermanentRedirect)
+		case "/temporary":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/gone":
+			http.Redirect(w, r, "/missing", http.StatusMovedPermanently)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	md := strings.Join([]string{
+		"# Title",
+		"",
+		"See [the *old* page](" + server.URL + "/old#usage \"Title\") and " + server.URL + "/temporary.",
+		"Also [one][ref] and [two][ref] and [gone](" + server.URL + "/gone).",
+		"",
+		"[ref]: " + server.URL + "/ref",
+	}, "\n")
+	html := `<p><a class="x" href="` + server.URL + `/old">old</a></p>`
+	mdPath, htmlPath := writeTestFile(t, dir, "doc.md", md), writeTestFile(t, dir, "page.html", html)
+
+	config = Config{InputPaths: []string{dir}, Timeout: time.Second, Workers: 2, Dialect: "gfm"}
+
+	var out bytes.Buffer
+	if err := runFix(&out, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	diff := out.String()
+	for _, want := range []string{
+		"+++ b/" + displaySource(mdPath),
+		"@@ -1,6 +1,6 @@\n",
+		"-See [the *old* page](" + server.URL + "/old#usage \"Title\")",
+		"+See [the *old* page](" + server.URL + "/new#usage \"Title\") and " + server.URL + "/temporary.",
+		"+[ref]: " + server.URL + "/new-ref\n\\ No newline at end of file\n",
+		"+<p><a class=\"x\" href=\"" + server.URL + "/new\">old</a></p>",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected %q in diff:\n%s", want, diff)
+		}
+	}
+	if data, _ := os.ReadFile(mdPath); string(data) != md {
+		t.Error("dry run changed the file")
+	}
+
+	out.Reset()
+	if err := runFix(&out, false, []string{"example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected hosts outside the allow-list to be left alone, got:\n%s", out.String())
+	}
+
+	if err := runFix(&out, false, []string{"127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(server.URL+"/old", server.URL+"/new", server.URL+"/ref", server.URL+"/new-ref").Replace(md)
+	if data, _ := os.ReadFile(mdPath); string(data) != want {
+		t.Errorf("unexpected fixed markdown:\n%s", data)
+	}
+	if data, _ := os.ReadFile(htmlPath); !strings.Contains(string(data), `href="`+server.URL+`/new"`) {
+		t.Errorf("unexpected fixed HTML:\n%s", data)
+	}
+}
+
+func TestFixFor(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+	config = Config{}
+
+	link := parser.Link{URL: "https://old.example/page#usage", Line: 3, Start: 10, End: 40}
+	moved := func(target string, code int) validator.LinkStatus {
+		return validator.LinkStatus{Link: link.URL, Valid: true, StatusCode: code, RedirectURL: target,
+			RedirectCode: http.StatusMovedPermanently, PermanentRedirect: true}
+	}
+	tests := []struct {
+		name   string
+		status validator.LinkStatus
+		hosts  []string
+		want   string
+	}{
+		{"same host", moved("https://old.example/new", http.StatusOK), nil, "https://old.example/new#usage"},
+		{"other host without allow-list", moved("https://parked.example/", http.StatusOK), nil, ""},
+		{"other host in allow-list", moved("https://new.example/page", http.StatusOK), []string{"*.example"}, "https://new.example/page#usage"},
+		{"other host outside allow-list", moved("https://new.example/page", http.StatusOK), []string{"old.example"}, ""},
+		{"redirect limit reached", moved("https://old.example/hop10", http.StatusMovedPermanently), nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit, ok := fixFor(link, tt.status, tt.hosts)
+			if got := edit.New; !ok && tt.want != "" || ok && got != tt.want {
+				t.Errorf("fixFor() = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "1\n2\n3\n4\nold\n6\n7\n8\n9\n10\n11\n12\n13\nold\n"
+	after := strings.ReplaceAll(before, "old", "new")
+	want := `--- a/f.md
++++ b/f.md
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-old
++new
+ 6
+ 7
+ 8
+@@ -11,4 +11,4 @@
+ 11
+ 12
+ 13
+-old
++new
+`
+	if got := unifiedDiff("f.md", []byte(before), []byte(after)); got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunFix_GFMLinkify(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	// A proxy stands in for www.example.com, so that GFM linkifies the bare link
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	proxy, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(saved http.RoundTripper) { http.DefaultTransport = saved }(http.DefaultTransport)
+	http.DefaultTransport = &http.Transport{Proxy: http.ProxyURL(proxy)}
+
+	md := "See www.example.com/old and [the page](http://www.example.com/old).\n"
+	path := writeTestFile(t, t.TempDir(), "a.md", md)
+	config = Config{InputPaths: []string{path}, Timeout: time.Second, Workers: 2, Dialect: "gfm"}
+
+	var out bytes.Buffer
+	if err := runFix(&out, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "See www.example.com/old and [the page](http://www.example.com/new).\n"
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("expected only the literal link to be rewritten, got:\n%s", data)
+	}
+}
+
+func TestApplyEdits(t *testing.T) {
+	src := []byte("a https://old.example/x b https://old.example/y\n")
+	edits := []linkEdit{
+		{Line: 1, Start: 2, End: 23, Old: "https://old.example/x", New: "https://new.example/x"},
+		// The source holds a different text at this position
+		{Line: 1, Start: 26, End: 47, Old: "https://old.example/z", New: "https://new.example/z"},
+	}
+	fixed, applied := applyEdits("a.md", src, edits)
+	if string(fixed) != "a https://new.example/x b https://old.example/y\n" {
+		t.Errorf("unexpected result: %q", fixed)
+	}
+	if len(applied) != 1 || applied[0].Old != "https://old.example/x" {
+		t.Errorf("expected the mismatched edit to be skipped, got %+v", applied)
+	}
+}
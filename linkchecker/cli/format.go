@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+)
+
+// fingerprinter assigns stable fingerprints to report entries. Line numbers are
+// left out so that edits above a link don't churn alerts; repeated entries within
+// the same source are told apart by their occurrence count.
+type fingerprinter map[string]int
+
+// next returns the fingerprint for the next entry identified by parts
+func (f fingerprinter) next(parts ...string) string {
+	key := strings.Join(parts, "\x00")
+	f[key]++
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, f[key])))
+	return hex.EncodeToString(sum[:])
+}
+
+// isSoftFailure reports whether a broken link is likely to be transient, such as
+// a timeout, rate limiting or a temporarily unavailable server
+func isSoftFailure(result Result) bool {
+	if result.Status != "invalid" {
+		return false
+	}
+	if result.ErrorKind == string(validator.ErrorTimeout) {
+		return true
+	}
+	switch result.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// relativeSourcePath turns a file path into a slash-separated path relative to the
+// working directory, which is how code scanning tools expect artifact URIs
+func relativeSourcePath(source string) string {
+	path := filepath.Clean(source)
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// escapeMarkdownCell keeps a value from breaking out of a Markdown table cell
+func escapeMarkdownCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "<", "&lt;", "\r", " ", "\n", " ").Replace(s)
+}
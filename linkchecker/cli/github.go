@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// githubStepSummaryEnv names the file GitHub Actions renders as the job summary
+const githubStepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// outputGitHub prints workflow commands that GitHub Actions turns into inline
+// annotations. Broken links are errors; transient failures, redirects and lint
+// findings are warnings, and broken links known from the baseline are notices.
+// When running inside a job, a Markdown summary is appended to
+// $GITHUB_STEP_SUMMARY as well.
+func outputGitHub(w io.Writer, output Output) error {
+	for _, result := range output.Results {
+		switch {
+		case result.Status == "invalid":
+			command, title := "error", "Broken link"
+			if isSoftFailure(result) {
+				command, title = "warning", "Unreachable link"
+			}
+			if result.Baselined {
+				command, title = "notice", "Known broken link"
+			}
+			if result.ErrorKind != "" {
+				title += " (" + result.ErrorKind + ")"
+			}
+			message := result.URL
+			if result.Error != "" {
+				message += ": " + result.Error
+			}
+			if err := writeGitHubCommand(w, command, result.Source, result.Line, result.Column, title, message); err != nil {
+				return err
+			}
+		case result.Status == "valid" && result.RedirectURL != "":
+			message := fmt.Sprintf("%s redirects to %s", result.URL, result.RedirectURL)
+			if result.RedirectCode != 0 {
+				message += fmt.Sprintf(" (%d)", result.RedirectCode)
+			}
+			if err := writeGitHubCommand(w, "warning", result.Source, result.Line, result.Column, "Redirected link", message); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, finding := range output.Findings {
+		if err := writeGitHubCommand(w, "warning", finding.Source, finding.Line, finding.Column, finding.Rule, finding.Message); err != nil {
+			return err
+		}
+	}
+
+	summaryPath := os.Getenv(githubStepSummaryEnv)
+	if summaryPath == "" {
+		return nil
+	}
+	file, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening job summary: %v", err)
+	}
+	defer file.Close()
+	return writeGitHubSummary(file, output)
+}
+
+// writeGitHubCommand prints a single ::error or ::warning workflow command
+func writeGitHubCommand(w io.Writer, command, source string, line, column int, title, message string) error {
+	var properties []string
+	// Annotations can only point at files in the repository, not at checked web pages
+	if source != "" && !isURL(source) {
+		properties = append(properties, "file="+escapeGitHubProperty(relativeSourcePath(source)))
+		if line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", line))
+			if column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", column))
+			}
+		}
+	} else if source != "" {
+		message = source + ": " + message
+	}
+	properties = append(properties, "title="+escapeGitHubProperty(title))
+
+	_, err := fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(properties, ","), escapeGitHubData(message))
+	return err
+}
+
+// writeGitHubSummary renders the Markdown job summary
+func writeGitHubSummary(w io.Writer, output Output) error {
+	var b strings.Builder
+	b.WriteString("## Link Check Results\n\n")
+	b.WriteString("| Total | Valid | Invalid | Suppressed | Lint Findings | Duration |\n")
+	b.WriteString("| ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %d | %s |\n\n",
+		output.Summary.Total, output.Summary.Valid, output.Summary.Invalid,
+		output.Summary.Suppressed, len(output.Findings), output.Summary.Duration)
+
+	var broken []Result
+	for _, result := range output.Results {
+		if result.Status == "invalid" {
+			broken = append(broken, result)
+		}
+	}
+	if len(broken) == 0 {
+		b.WriteString(":white_check_mark: All links are valid.\n")
+	} else {
+		fmt.Fprintf(&b, "### Broken Links (%d)\n\n", len(broken))
+		b.WriteString("| Source | Line | URL | Error |\n")
+		b.WriteString("| --- | ---: | --- | --- |\n")
+		for _, result := range broken {
+			source := result.Source
+			if !isURL(source) {
+				source = relativeSourcePath(source)
+			}
+			line := ""
+			if result.Line > 0 {
+				line = fmt.Sprint(result.Line)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				escapeMarkdownCell(source), line, escapeMarkdownCell(result.URL), escapeMarkdownCell(result.Error))
+		}
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeGitHubData escapes the message part of a workflow command
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
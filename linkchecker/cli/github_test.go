@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputGitHub(t *testing.T) {
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv(githubStepSummaryEnv, summary)
+
+	output := Output{
+		Results: []Result{
+			{URL: "https://ok.example", Status: "valid", Source: "README.md", Line: 3},
+			{URL: "https://gone.example", Status: "invalid", StatusCode: 404, Error: "404 Not Found",
+				ErrorKind: "not-found", Source: "docs/a,b.md", Line: 7, Column: 5},
+			{URL: "https://slow.example", Status: "invalid", Error: "timeout", ErrorKind: "timeout", Source: "README.md", Line: 9},
+			{URL: "http://moved.example", Status: "valid", StatusCode: 200, Source: "README.md", Line: 11,
+				RedirectURL: "https://moved.example/", RedirectCode: 301},
+		},
+		Findings: []Finding{
+			{Rule: "unused-reference", Message: "Reference definition \"x\" is never used", Source: "README.md", Line: 20, Column: 1},
+		},
+	}
+	output.Summary.Total, output.Summary.Valid, output.Summary.Invalid = 4, 2, 2
+
+	var buf bytes.Buffer
+	if err := outputGitHub(&buf, output); err != nil {
+		t.Fatalf("outputGitHub error: %v", err)
+	}
+
+	want := []string{
+		"::error file=docs/a%2Cb.md,line=7,col=5,title=Broken link (not-found)::https://gone.example: 404 Not Found",
+		"::warning file=README.md,line=9,title=Unreachable link (timeout)::https://slow.example: timeout",
+		"::warning file=README.md,line=11,title=Redirected link::http://moved.example redirects to https://moved.example/ (301)",
+		"::warning file=README.md,line=20,col=1,title=unused-reference::Reference definition \"x\" is never used",
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("expected %d commands, got %d:\n%s", len(want), len(got), buf.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command %d:\n got: %s\nwant: %s", i, got[i], want[i])
+		}
+	}
+
+	data, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatalf("job summary not written: %v", err)
+	}
+	if !strings.Contains(string(data), "| docs/a,b.md | 7 | https://gone.example | 404 Not Found |") {
+		t.Errorf("job summary is missing the broken link:\n%s", data)
+	}
+}
+
+func TestEscapeGitHub(t *testing.T) {
+	if got := escapeGitHubData("50%\nnext"); got != "50%25%0Anext" {
+		t.Errorf("escapeGitHubData = %q", got)
+	}
+	if got := escapeGitHubProperty("a:b,c"); got != "a%3Ab%2Cc" {
+		t.Errorf("escapeGitHubProperty = %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// gitlabIssue is an entry of a GitLab Code Quality report
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// outputGitLabCodeQuality writes a Code Quality report that GitLab shows in merge
+// request widgets. Broken links are major, transient failures minor, and redirects
+// and lint findings informational.
+func outputGitLabCodeQuality(w io.Writer, output Output) error {
+	issues := []gitlabIssue{}
+	fingerprints := make(fingerprinter)
+	for _, result := range output.Results {
+		var issue gitlabIssue
+		switch {
+		case result.Status == "invalid":
+			checkName := result.ErrorKind
+			if checkName == "" {
+				checkName = "broken-link"
+			}
+			severity := "major"
+			if isSoftFailure(result) {
+				severity = "minor"
+			}
+			description := fmt.Sprintf("Broken link %s", result.URL)
+			if result.Error != "" {
+				description += ": " + result.Error
+			}
+			issue = gitlabIssue{Description: description, CheckName: checkName, Severity: severity}
+		case result.Status == "valid" && result.RedirectURL != "":
+			issue = gitlabIssue{
+				Description: fmt.Sprintf("Link %s redirects to %s", result.URL, result.RedirectURL),
+				CheckName:   "redirect",
+				Severity:    "info",
+			}
+		default:
+			continue
+		}
+		issue.Fingerprint = fingerprints.next(issue.CheckName, result.Source, result.URL)
+		issue.Location = gitlabLocationFor(result.Source, result.Line)
+		issues = append(issues, issue)
+	}
+
+	for _, finding := range output.Findings {
+		issues = append(issues, gitlabIssue{
+			Description: finding.Message,
+			CheckName:   finding.Rule,
+			Fingerprint: fingerprints.next(finding.Rule, finding.Source, finding.Message),
+			Severity:    "info",
+			Location:    gitlabLocationFor(finding.Source, finding.Line),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
+
+// gitlabLocationFor builds a location; GitLab requires a line, so 1 is used when unknown
+func gitlabLocationFor(source string, line int) gitlabLocation {
+	path := source
+	if !isURL(source) {
+		path = relativeSourcePath(source)
+	}
+	if line < 1 {
+		line = 1
+	}
+	return gitlabLocation{Path: path, Lines: gitlabLines{Begin: line}}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestOutputGitLabCodeQuality(t *testing.T) {
+	output := Output{
+		Results: []Result{
+			{URL: "https://ok.example", Status: "valid", Source: "README.md", Line: 3},
+			{URL: "https://gone.example", Status: "invalid", StatusCode: 404, Error: "404 Not Found",
+				ErrorKind: "not-found", Source: "README.md", Line: 7},
+			{URL: "https://gone.example", Status: "invalid", StatusCode: 404, Error: "404 Not Found",
+				ErrorKind: "not-found", Source: "README.md", Line: 12},
+			{URL: "https://busy.example", Status: "invalid", StatusCode: 503, ErrorKind: "http-server-error", Source: "README.md"},
+			{URL: "http://moved.example", Status: "valid", Source: "README.md", Line: 11, RedirectURL: "https://moved.example/"},
+		},
+		Findings: []Finding{{Rule: "undefined-reference", Message: "Undefined reference \"y\"", Source: "README.md", Line: 4}},
+	}
+
+	var buf bytes.Buffer
+	if err := outputGitLabCodeQuality(&buf, output); err != nil {
+		t.Fatalf("outputGitLabCodeQuality error: %v", err)
+	}
+
+	var issues []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(issues) != 5 {
+		t.Fatalf("expected 5 issues, got %d", len(issues))
+	}
+
+	severities := []string{"major", "major", "minor", "info", "info"}
+	for i, severity := range severities {
+		if issues[i].Severity != severity {
+			t.Errorf("issue %d: expected severity %s, got %s", i, severity, issues[i].Severity)
+		}
+	}
+	if issues[0].CheckName != "not-found" || issues[0].Location.Path != "README.md" || issues[0].Location.Lines.Begin != 7 {
+		t.Errorf("unexpected first issue: %+v", issues[0])
+	}
+	if issues[0].Fingerprint == issues[1].Fingerprint {
+		t.Error("repeated links should get distinct fingerprints")
+	}
+	if issues[2].Location.Lines.Begin != 1 {
+		t.Errorf("expected line 1 for unknown positions, got %d", issues[2].Location.Lines.Begin)
+	}
+
+	// Fingerprints must not change when links move to other lines
+	output.Results[1].Line, output.Results[2].Line = 8, 13
+	var moved bytes.Buffer
+	if err := outputGitLabCodeQuality(&moved, output); err != nil {
+		t.Fatal(err)
+	}
+	var movedIssues []gitlabIssue
+	if err := json.Unmarshal(moved.Bytes(), &movedIssues); err != nil {
+		t.Fatal(err)
+	}
+	if movedIssues[0].Fingerprint != issues[0].Fingerprint {
+		t.Error("fingerprint changed after the link moved")
+	}
+}
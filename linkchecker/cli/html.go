@@ -0,0 +1,188 @@
+package cli
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed html_report.tmpl
+var htmlReportTemplate string
+
+var htmlReport = template.Must(template.New("report").Parse(htmlReportTemplate))
+
+// htmlReportData is the view model rendered by html_report.tmpl
+type htmlReportData struct {
+	Generated  string
+	Summary    htmlSummary
+	ErrorKinds []htmlCount
+	BySource   []htmlGroup
+	ByDomain   []htmlGroup
+	Findings   []htmlFinding
+}
+
+type htmlSummary struct {
+	Total, Valid, Invalid, Suppressed, Skipped, Findings int
+	Duration                                             string
+}
+
+type htmlCount struct {
+	Name  string
+	Count int
+}
+
+// htmlGroup is one collapsible table of results sharing a source or domain
+type htmlGroup struct {
+	Name    string
+	Href    template.URL
+	Invalid int
+	Rows    []htmlRow
+}
+
+type htmlRow struct {
+	Result
+	Domain string
+	// Location is the source file or page with its line, LocationHref opens it
+	Location     string
+	LocationHref template.URL
+	// DurationNanos sorts the Duration column, -1 when the link was not timed
+	DurationNanos int64
+}
+
+type htmlFinding struct {
+	Finding
+	Location     string
+	LocationHref template.URL
+}
+
+// outputHTML writes a self-contained HTML report with inline styles and scripts,
+// so it can be archived as a CI artifact and opened without network access
+func outputHTML(w io.Writer, output Output) error {
+	data := htmlReportData{
+		Generated: time.Now().Format(time.RFC1123),
+		Summary: htmlSummary{
+			Total:      output.Summary.Total,
+			Valid:      output.Summary.Valid,
+			Invalid:    output.Summary.Invalid,
+			Suppressed: output.Summary.Suppressed,
+			Skipped:    output.Summary.Skipped,
+			Findings:   len(output.Findings),
+			Duration:   output.Summary.Duration,
+		},
+	}
+
+	kinds := make(map[string]int)
+	var sources, domains []string
+	bySource := make(map[string]*htmlGroup)
+	byDomain := make(map[string]*htmlGroup)
+	for _, result := range output.Results {
+		row := htmlRow{Result: result, Domain: linkDomain(result.URL), DurationNanos: -1}
+		if d, err := time.ParseDuration(result.Duration); err == nil {
+			row.DurationNanos = int64(d)
+		}
+		row.Location, row.LocationHref = sourceLocation(result.Source, result.Line)
+		if result.Status == "invalid" && result.ErrorKind != "" {
+			kinds[result.ErrorKind]++
+		}
+
+		if _, ok := bySource[result.Source]; !ok {
+			_, href := sourceLocation(result.Source, 0)
+			bySource[result.Source] = &htmlGroup{Name: displaySource(result.Source), Href: href}
+			sources = append(sources, result.Source)
+		}
+		if _, ok := byDomain[row.Domain]; !ok {
+			byDomain[row.Domain] = &htmlGroup{Name: row.Domain}
+			domains = append(domains, row.Domain)
+		}
+		for _, group := range []*htmlGroup{bySource[result.Source], byDomain[row.Domain]} {
+			group.Rows = append(group.Rows, row)
+			if result.Status == "invalid" {
+				group.Invalid++
+			}
+		}
+	}
+
+	// Groups with broken links come first so they are seen without scrolling
+	data.BySource = sortedGroups(sources, bySource)
+	data.ByDomain = sortedGroups(domains, byDomain)
+
+	for name, count := range kinds {
+		data.ErrorKinds = append(data.ErrorKinds, htmlCount{Name: name, Count: count})
+	}
+	sort.Slice(data.ErrorKinds, func(i, j int) bool {
+		if data.ErrorKinds[i].Count != data.ErrorKinds[j].Count {
+			return data.ErrorKinds[i].Count > data.ErrorKinds[j].Count
+		}
+		return data.ErrorKinds[i].Name < data.ErrorKinds[j].Name
+	})
+
+	for _, finding := range output.Findings {
+		f := htmlFinding{Finding: finding}
+		f.Location, f.LocationHref = sourceLocation(finding.Source, finding.Line)
+		data.Findings = append(data.Findings, f)
+	}
+
+	return htmlReport.Execute(w, data)
+}
+
+func sortedGroups(keys []string, groups map[string]*htmlGroup) []htmlGroup {
+	sorted := make([]htmlGroup, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, *groups[key])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if (sorted[i].Invalid > 0) != (sorted[j].Invalid > 0) {
+			return sorted[i].Invalid > 0
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// linkDomain returns the host of an external link, or a placeholder for local links
+func linkDomain(link string) string {
+	if u, err := url.Parse(link); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "(local)"
+}
+
+// displaySource shortens local paths relative to the working directory
+func displaySource(source string) string {
+	if isURL(source) {
+		return source
+	}
+	return relativeSourcePath(source)
+}
+
+// sourceLocation returns a label such as "docs/guide.md:12" and a link that opens
+// the source. Local files are linked with file:// URLs and a #L anchor for the line.
+func sourceLocation(source string, line int) (string, template.URL) {
+	label := displaySource(source)
+	if line > 0 {
+		label += ":" + strconv.Itoa(line)
+	}
+	if isURL(source) {
+		return label, template.URL(source)
+	}
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return label, ""
+	}
+	path := filepath.ToSlash(abs)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths need a leading slash: file:///C:/docs/guide.md
+		path = "/" + path
+	}
+	href := (&url.URL{Scheme: "file", Path: path}).String()
+	if line > 0 {
+		href += "#L" + strconv.Itoa(line)
+	}
+	return label, template.URL(href)
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOutputHTML(t *testing.T) {
+	output := Output{
+		Results: []Result{
+			{URL: "https://ok.example/a", Status: "valid", StatusCode: 200, Source: "README.md", Line: 3, Duration: "1m2.5s"},
+			{URL: "https://gone.example/<b>", Status: "invalid", StatusCode: 404, Error: "404 Not Found",
+				ErrorKind: "not-found", Source: "docs/guide.md", Line: 7},
+			{URL: "./missing.md", Status: "invalid", Error: "file does not exist", ErrorKind: "file-not-found", Source: "docs/guide.md", Line: 9},
+		},
+		Findings: []Finding{{Rule: "unused-reference", Message: "Reference definition \"x\" is never used", Source: "README.md", Line: 20}},
+	}
+	output.Summary.Total, output.Summary.Valid, output.Summary.Invalid = 3, 1, 2
+
+	var buf bytes.Buffer
+	if err := outputHTML(&buf, output); err != nil {
+		t.Fatalf("outputHTML error: %v", err)
+	}
+	report := buf.String()
+
+	for _, external := range []string{"<link ", "<script src", "@import"} {
+		if strings.Contains(report, external) {
+			t.Errorf("report must be self-contained, found %q", external)
+		}
+	}
+	if strings.Contains(report, "/<b>") {
+		t.Error("URLs must be escaped")
+	}
+	for _, want := range []string{
+		`data-status="invalid" data-kind="not-found"`,
+		"docs/guide.md:7",
+		"#L7",
+		`<option value="file-not-found">`,
+		"gone.example",
+		"(local)",
+		"unused-reference",
+		`<td data-sort="62500000000">1m2.5s</td>`,
+		`<td data-sort="-1"></td>`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q", want)
+		}
+	}
+
+	// Sources with broken links are listed before the others
+	if strings.Index(report, ">docs/guide.md<") > strings.Index(report, ">README.md<") {
+		t.Error("expected docs/guide.md with broken links before README.md")
+	}
+}
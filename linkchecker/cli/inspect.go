@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+	"github.com/spf13/cobra"
+)
+
+// certificateWarning is how long before expiry a certificate is highlighted
+const certificateWarning = 30 * 24 * time.Hour
+
+// inspectReport is the JSON form of an inspection
+type inspectReport struct {
+	URL string `json:"url"`
+	// IgnoredBy is the --ignore pattern that would skip the URL in a normal run
+	IgnoredBy string           `json:"ignored_by,omitempty"`
+	Rule      string           `json:"rule"`
+	Result    Result           `json:"result"`
+	Permanent bool             `json:"permanent_redirect,omitempty"`
+	Attempts  []inspectAttempt `json:"attempts"`
+}
+
+type inspectAttempt struct {
+	Number     int          `json:"number"`
+	Method     string       `json:"method"`
+	Comparison bool         `json:"comparison,omitempty"`
+	Duration   string       `json:"duration"`
+	Error      string       `json:"error,omitempty"`
+	Hops       []inspectHop `json:"hops"`
+}
+
+type inspectHop struct {
+	URL        string              `json:"url"`
+	StatusCode int                 `json:"status_code,omitempty"`
+	Status     string              `json:"status,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	Addresses  []string            `json:"addresses,omitempty"`
+	RemoteAddr string              `json:"remote_addr,omitempty"`
+	ReusedConn bool                `json:"reused_connection,omitempty"`
+	Timings    map[string]string   `json:"timings"`
+	TLS        *inspectTLS         `json:"tls,omitempty"`
+	Header     map[string][]string `json:"headers,omitempty"`
+}
+
+type inspectTLS struct {
+	Version      string               `json:"version"`
+	CipherSuite  string               `json:"cipher_suite"`
+	ServerName   string               `json:"server_name"`
+	Certificates []inspectCertificate `json:"certificates"`
+}
+
+type inspectCertificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+func newInspectCommand() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "inspect <url>",
+		Short: "Check a single URL and print every detail of the check",
+		Long: `Check a single URL with the same logic as a normal run and print the details:
+DNS results, each redirect hop with its headers, the TLS certificate chain and
+its expiry, the outcome of HEAD and GET requests, timings per phase, and the
+rule that decided the result.`,
+		Example: `  linkchecker inspect https://example.com/moved
+  linkchecker inspect --timeout=5s --format=json https://example.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", format)
+			}
+			if err := validateColor(config.Color); err != nil {
+				return err
+			}
+			if !isURL(args[0]) || hasUncheckableScheme(args[0]) || strings.HasPrefix(args[0], "//") {
+				return fmt.Errorf("not an http or https URL: %s", args[0])
+			}
+			if err := prepareCheck(args); err != nil {
+				return err
+			}
+
+			report := inspect(args[0])
+			var err error
+			if format == "json" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				err = encoder.Encode(report)
+			} else {
+				err = outputInspectText(os.Stdout, report, time.Now())
+			}
+			if err != nil {
+				return err
+			}
+			if report.Result.Status == "invalid" {
+				return fmt.Errorf("link is broken: %s", report.Result.Error)
+			}
+			return nil
+		},
+	}
+	addCheckFlags(cmd)
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: 'text' or 'json'")
+	cmd.Flags().StringVar(&config.Color, "color", "auto",
+		"Color terminal output: 'auto' (terminals only, honors NO_COLOR), 'always' or 'never'")
+	return cmd
+}
+
+// inspect checks a URL with the validator and records the details of every request
+func inspect(url string) inspectReport {
+	insp := validator.Inspect(url, validator.Options{Timeout: config.Timeout, Logger: logger})
+
+	report := inspectReport{
+		URL:       url,
+		IgnoredBy: ignorePatternFor(url),
+		Rule:      insp.Rule,
+		Result:    Result{URL: url},
+		Permanent: insp.Status.PermanentRedirect,
+	}
+	applyStatus(&report.Result, insp.Status)
+	if report.IgnoredBy != "" {
+		report.Rule = fmt.Sprintf("ignored by --ignore pattern '%s' in a normal run; %s", report.IgnoredBy, report.Rule)
+	}
+
+	for _, attempt := range insp.Attempts {
+		a := inspectAttempt{
+			Number:     attempt.Number,
+			Method:     attempt.Method,
+			Comparison: attempt.Comparison,
+			Duration:   attempt.Duration.String(),
+			Error:      attempt.Error,
+			Hops:       []inspectHop{},
+		}
+		for _, hop := range attempt.Hops {
+			a.Hops = append(a.Hops, inspectHop{
+				URL:        hop.URL,
+				StatusCode: hop.StatusCode,
+				Status:     hop.Status,
+				Error:      hop.Error,
+				Addresses:  hop.Addresses,
+				RemoteAddr: hop.RemoteAddr,
+				ReusedConn: hop.ReusedConn,
+				Timings:    hopTimings(hop.Timings),
+				TLS:        inspectTLSFor(hop.TLS),
+				Header:     hop.Header,
+			})
+		}
+		report.Attempts = append(report.Attempts, a)
+	}
+	return report
+}
+
+// inspectTLSFor converts the TLS details of a request
+func inspectTLSFor(info *validator.TLSInfo) *inspectTLS {
+	if info == nil {
+		return nil
+	}
+	tls := &inspectTLS{Version: info.Version, CipherSuite: info.CipherSuite, ServerName: info.ServerName}
+	for _, cert := range info.Certificates {
+		tls.Certificates = append(tls.Certificates, inspectCertificate(cert))
+	}
+	return tls
+}
+
+// hopTimings lists the measured phases of a request; phases that did not happen are left out
+func hopTimings(t validator.Timings) map[string]string {
+	timings := map[string]string{"total": t.Total.String()}
+	for name, d := range map[string]time.Duration{"dns": t.DNS, "connect": t.Connect, "tls": t.TLS, "first_byte": t.FirstByte} {
+		if d > 0 {
+			timings[name] = d.String()
+		}
+	}
+	return timings
+}
+
+// outputInspectText prints an inspection for humans; now is used for certificate expiry
+func outputInspectText(w io.Writer, report inspectReport, now time.Time) error {
+	colors := palette(colorEnabled(config.Color, w))
+
+	fmt.Fprintf(w, "%s %s\n\n", colors.bold("Inspecting"), report.URL)
+
+	result := report.Result
+	switch result.Status {
+	case "valid":
+		fmt.Fprintf(w, "Result:    %s valid (%d) in %s\n", colors.green("✓"), result.StatusCode, result.Duration)
+	default:
+		fmt.Fprintf(w, "Result:    %s invalid: %s\n", colors.red("✗"), result.Error)
+	}
+	fmt.Fprintf(w, "Rule:      %s\n", report.Rule)
+	if result.RedirectURL != "" {
+		kind := "temporary"
+		if report.Permanent {
+			kind = "permanent"
+		}
+		fmt.Fprintf(w, "Redirect:  %d → %s (%s)\n", result.RedirectCode, result.RedirectURL, kind)
+	}
+
+	for _, attempt := range report.Attempts {
+		note := ""
+		if attempt.Comparison {
+			note = ", comparison only"
+		}
+		fmt.Fprintf(w, "\n%s\n", colors.bold(fmt.Sprintf("Attempt %d: %s (%s%s)", attempt.Number, attempt.Method, attempt.Duration, note)))
+		for i, hop := range attempt.Hops {
+			writeInspectHop(w, colors, i+1, hop, now)
+		}
+		if attempt.Error != "" {
+			fmt.Fprintf(w, "  Error: %s\n", colors.red(attempt.Error))
+		}
+	}
+	return nil
+}
+
+// writeInspectHop prints one request of an attempt
+func writeInspectHop(w io.Writer, colors palette, n int, hop inspectHop, now time.Time) {
+	var outcome string
+	switch {
+	case hop.Error != "":
+		outcome = colors.red(hop.Error)
+	case hop.StatusCode >= 400:
+		outcome = colors.red(hop.Status)
+	case hop.StatusCode >= 300:
+		outcome = colors.yellow(hop.Status)
+	default:
+		outcome = colors.green(hop.Status)
+	}
+	fmt.Fprintf(w, "  Hop %d: %s → %s\n", n, hop.URL, outcome)
+
+	if len(hop.Addresses) > 0 {
+		fmt.Fprintf(w, "    DNS:      %s\n", strings.Join(hop.Addresses, ", "))
+	}
+	if hop.RemoteAddr != "" {
+		connection := "new connection"
+		if hop.ReusedConn {
+			connection = "reused connection"
+		}
+		fmt.Fprintf(w, "    Remote:   %s (%s)\n", hop.RemoteAddr, connection)
+	}
+	var timings []string
+	for _, phase := range []string{"dns", "connect", "tls", "first_byte", "total"} {
+		if d, ok := hop.Timings[phase]; ok {
+			timings = append(timings, strings.ReplaceAll(phase, "_", " ")+" "+d)
+		}
+	}
+	fmt.Fprintf(w, "    Timings:  %s\n", colors.dim(strings.Join(timings, ", ")))
+
+	if hop.TLS != nil && !hop.ReusedConn {
+		fmt.Fprintf(w, "    TLS:      %s, %s, server name %s\n", hop.TLS.Version, hop.TLS.CipherSuite, hop.TLS.ServerName)
+		for i, cert := range hop.TLS.Certificates {
+			fmt.Fprintf(w, "      Certificate %d: %s\n", i+1, cert.Subject)
+			fmt.Fprintf(w, "        Issuer:  %s\n", cert.Issuer)
+			if len(cert.DNSNames) > 0 {
+				fmt.Fprintf(w, "        Names:   %s\n", strings.Join(cert.DNSNames, ", "))
+			}
+			fmt.Fprintf(w, "        Valid:   %s to %s (%s)\n",
+				cert.NotBefore.Format(time.DateOnly), cert.NotAfter.Format(time.DateOnly), certificateExpiry(colors, cert.NotAfter, now))
+		}
+	}
+
+	if len(hop.Header) > 0 {
+		fmt.Fprintln(w, "    Headers:")
+		names := make([]string, 0, len(hop.Header))
+		for name := range hop.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, value := range hop.Header[name] {
+				fmt.Fprintf(w, "      %s: %s\n", http.CanonicalHeaderKey(name), value)
+			}
+		}
+	}
+}
+
+// certificateExpiry describes how long a certificate remains valid
+func certificateExpiry(colors palette, notAfter, now time.Time) string {
+	days := int(notAfter.Sub(now).Hours() / 24)
+	switch {
+	case !now.Before(notAfter):
+		return colors.red(fmt.Sprintf("expired %d days ago", -days))
+	case notAfter.Sub(now) < certificateWarning:
+		return colors.yellow(fmt.Sprintf("expires in %d days", days))
+	default:
+		return fmt.Sprintf("expires in %d days", days)
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInspect(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/target", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config = Config{Timeout: time.Second, Color: "never", IgnoreList: []string{"127.0.0.1"}}
+	if err := compileIgnorePatterns(); err != nil {
+		t.Fatal(err)
+	}
+	report := inspect(server.URL + "/moved")
+
+	var buf bytes.Buffer
+	if err := outputInspectText(&buf, report, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	for _, want := range []string{
+		"Result:    ✓ valid (200)",
+		"ignored by --ignore pattern '127.0.0.1' in a normal run; status 200 is 2xx or 3xx: valid",
+		"Redirect:  301 → " + server.URL + "/target (permanent)",
+		"Attempt 1: HEAD",
+		"Hop 1: " + server.URL + "/moved → 301 Moved Permanently",
+		"Location: /target",
+		"Hop 2: " + server.URL + "/target → 200 OK",
+		"(new connection)",
+		"Attempt 2: GET",
+		", comparison only)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Result   Result `json:"result"`
+		Attempts []struct {
+			Hops []struct {
+				StatusCode int               `json:"status_code"`
+				Timings    map[string]string `json:"timings"`
+			} `json:"hops"`
+		} `json:"attempts"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Result.Status != "valid" || len(decoded.Attempts) != 2 || decoded.Attempts[0].Hops[0].StatusCode != 301 ||
+		decoded.Attempts[0].Hops[0].Timings["total"] == "" {
+		t.Errorf("unexpected JSON report: %s", data)
+	}
+}
+
+func TestCertificateExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	colors := palette(false)
+	tests := map[time.Time]string{
+		now.AddDate(0, 0, 90): "expires in 90 days",
+		now.AddDate(0, 0, 10): "expires in 10 days",
+		now.AddDate(0, 0, -3): "expired 3 days ago",
+	}
+	for notAfter, want := range tests {
+		if got := certificateExpiry(colors, notAfter, now); got != want {
+			t.Errorf("certificateExpiry(%v) = %q, want %q", notAfter, got, want)
+		}
+	}
+}
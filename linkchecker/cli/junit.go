@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+
+	duration time.Duration
+	checked  map[string]bool
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+func outputJUnit(w io.Writer, output Output) error {
+	report := junitTestSuites{Name: "linkchecker"}
+	suites := make(map[string]*junitTestSuite)
+	var order []string
+	suiteFor := func(source string) *junitTestSuite {
+		if suite, ok := suites[source]; ok {
+			return suite
+		}
+		suites[source] = &junitTestSuite{Name: source, checked: make(map[string]bool)}
+		order = append(order, source)
+		return suites[source]
+	}
+
+	// Suites are always sources, since CI servers map them to files. A link used
+	// several times is checked once, so its duration only counts once.
+	var total time.Duration
+	checked := make(map[string]bool)
+	for _, result := range output.Results {
+		suite := suiteFor(result.Source)
+		duration, _ := time.ParseDuration(result.Duration)
+		testCase := junitTestCase{
+			Name:      result.URL,
+			ClassName: result.Source,
+			Time:      junitSeconds(duration),
+			Line:      result.Line,
+		}
+		if result.Line > 0 {
+			testCase.Name = fmt.Sprintf("%s (line %d)", result.URL, result.Line)
+		}
+		if !isURL(result.Source) {
+			testCase.File = result.Source
+		}
+
+		switch {
+		case result.Baselined:
+			testCase.Skipped = &junitSkipped{Message: "Known broken link from the baseline: " + result.Error}
+			suite.Skipped++
+		case result.Status == "invalid":
+			testCase.Failure = &junitFailure{
+				Message: result.Error,
+				Type:    result.ErrorKind,
+				Text:    junitFailureText(result),
+			}
+			suite.Failures++
+		case result.Status == "suppressed", result.Status == "skipped":
+			testCase.Skipped = &junitSkipped{Message: result.Reason}
+			suite.Skipped++
+		}
+
+		suite.Tests++
+		if !suite.checked[result.URL] {
+			suite.checked[result.URL] = true
+			suite.duration += duration
+		}
+		if !checked[result.URL] {
+			checked[result.URL] = true
+			total += duration
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	// Lint findings fail like broken links so they show up in the CI test view
+	for _, finding := range output.Findings {
+		suite := suiteFor(finding.Source)
+		suite.Tests++
+		suite.Failures++
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%s (line %d)", finding.Rule, finding.Line),
+			ClassName: finding.Source,
+			Time:      junitSeconds(0),
+			File:      finding.Source,
+			Line:      finding.Line,
+			Failure: &junitFailure{
+				Message: finding.Message,
+				Type:    finding.Rule,
+				Text:    fmt.Sprintf("%s:%d:%d %s", finding.Source, finding.Line, finding.Column, finding.Message),
+			},
+		})
+	}
+
+	for _, source := range order {
+		suite := suites[source]
+		suite.Time = junitSeconds(suite.duration)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, *suite)
+	}
+	report.Time = junitSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitSeconds formats a duration the way JUnit expects it
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// junitFailureText lists the details of a broken link in the failure body
+func junitFailureText(result Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "URL: %s\n", result.URL)
+	if result.Line > 0 {
+		fmt.Fprintf(&b, "Location: %s:%d:%d\n", result.Source, result.Line, result.Column)
+	}
+	if result.StatusCode > 0 {
+		fmt.Fprintf(&b, "Status: %d\n", result.StatusCode)
+	}
+	if result.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", result.Error)
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestOutputJUnit(t *testing.T) {
+	output := Output{
+		Results: []Result{
+			{URL: "https://ok.example", Status: "valid", StatusCode: 200, Source: "README.md", Line: 3, Duration: "120ms"},
+			{URL: "https://gone.example", Status: "invalid", StatusCode: 404, Error: "404 Not Found",
+				ErrorKind: "not-found", Source: "README.md", Line: 7, Duration: "80ms"},
+			{URL: "https://ok.example", Status: "valid", StatusCode: 200, Source: "README.md", Line: 9, Duration: "120ms"},
+			{URL: "https://flaky.example", Status: "suppressed", Reason: "flaky", Source: "docs/guide.md", Line: 2},
+			{URL: "https://ok.example", Status: "valid", StatusCode: 200, Source: "docs/guide.md", Line: 5, Duration: "120ms"},
+		},
+	}
+	// Suites stay per source whatever the grouping of other formats is
+	defer func(saved Config) { config = saved }(config)
+	config.GroupBy = "domain"
+
+	var buf bytes.Buffer
+	if err := outputJUnit(&buf, output); err != nil {
+		t.Fatalf("outputJUnit error: %v", err)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, buf.String())
+	}
+	if report.Tests != 5 || report.Failures != 1 || report.Skipped != 1 {
+		t.Errorf("unexpected totals: tests=%d failures=%d skipped=%d", report.Tests, report.Failures, report.Skipped)
+	}
+	if len(report.Suites) != 2 || report.Suites[0].Name != "README.md" || report.Suites[1].Name != "docs/guide.md" {
+		t.Fatalf("expected one suite per source, got %+v", report.Suites)
+	}
+
+	readme := report.Suites[0]
+	if readme.Time != "0.200" {
+		t.Errorf("expected suite time 0.200 with the repeated link counted once, got %s", readme.Time)
+	}
+	if report.Suites[1].Time != "0.120" || report.Time != "0.200" {
+		t.Errorf("expected guide time 0.120 and total time 0.200, got %s and %s", report.Suites[1].Time, report.Time)
+	}
+	failure := readme.TestCases[1].Failure
+	if failure == nil || failure.Type != "not-found" || failure.Message != "404 Not Found" {
+		t.Fatalf("unexpected failure: %+v", failure)
+	}
+	if !bytes.Contains([]byte(failure.Text), []byte("Status: 404")) {
+		t.Errorf("failure body does not contain the status code: %q", failure.Text)
+	}
+	if skipped := report.Suites[1].TestCases[0].Skipped; skipped == nil || skipped.Message != "flaky" {
+		t.Errorf("expected suppressed link to be skipped, got %+v", skipped)
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// defaultMarkdownMaxSize keeps reports below GitHub's 65536 character limit for comments
+const defaultMarkdownMaxSize = 65000
+
+// markdownFooterReserve is kept free for closing tags and the truncation footer
+const markdownFooterReserve = 256
+
+// markdownSection is a collapsible block of table rows for one source
+type markdownSection struct {
+	summary string
+	open    bool
+	header  string
+	rows    []string
+}
+
+// outputMarkdown writes a compact report for pull request comments: a summary
+// table followed by a collapsible section per source, broken links first
+func outputMarkdown(w io.Writer, output Output) error {
+	_, err := io.WriteString(w, renderMarkdown(output, config.MarkdownMaxSize))
+	return err
+}
+
+// renderMarkdown builds the report and truncates it to at most limit bytes
+func renderMarkdown(output Output, limit int) string {
+	var b strings.Builder
+	b.WriteString("## Link Check Results\n\n")
+	b.WriteString("| Total | ✅ Valid | ❌ Invalid | ⊘ Suppressed | Lint Findings | Duration |\n")
+	b.WriteString("| ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %d | %s |\n\n",
+		output.Summary.Total, output.Summary.Valid, output.Summary.Invalid,
+		output.Summary.Suppressed, len(output.Findings), output.Summary.Duration)
+
+	sections := markdownSections(output)
+	remaining := 0
+	for _, section := range sections {
+		remaining += len(section.rows)
+	}
+
+	const closing = "\n</details>\n\n"
+	fits := func(s string) bool {
+		return limit == 0 || b.Len()+len(s)+len(closing)+markdownFooterReserve <= limit
+	}
+
+sections:
+	for _, section := range sections {
+		details := "<details>"
+		if section.open {
+			details = "<details open>"
+		}
+		opening := fmt.Sprintf("%s\n<summary>%s</summary>\n\n%s", details, section.summary, section.header)
+		if len(section.rows) == 0 || !fits(opening+section.rows[0]) {
+			break
+		}
+		b.WriteString(opening)
+		for _, row := range section.rows {
+			if !fits(row) {
+				b.WriteString(closing)
+				break sections
+			}
+			b.WriteString(row)
+			remaining--
+		}
+		b.WriteString(closing)
+	}
+
+	if remaining > 0 {
+		fmt.Fprintf(&b, "_… and %d more not shown to stay within the %d byte limit._\n", remaining, limit)
+	}
+	return b.String()
+}
+
+// markdownSections groups results as selected with --group-by, by source unless
+// set otherwise. Groups with broken links come first, and within a group broken
+// links are listed before the others.
+func markdownSections(output Output) []markdownSection {
+	groups := groupResults(config.GroupBy, output.Results)
+	sections := make([]markdownSection, 0, len(groups)+1)
+	for _, group := range groups {
+		results := group.Results
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Status == "invalid" && results[j].Status != "invalid"
+		})
+
+		section := markdownSection{header: "| | Line | URL | Details |\n| --- | ---: | --- | --- |\n"}
+		invalid := 0
+		for _, result := range results {
+			icon, details := "✅", ""
+			switch result.Status {
+			case "invalid":
+				icon, details = "❌", result.Error
+				invalid++
+			case "suppressed":
+				icon, details = "⊘", result.Reason
+			case "skipped":
+				icon, details = "⏭", "skipped: "+result.Reason
+			default:
+				if result.RedirectURL != "" {
+					details = "redirects to " + result.RedirectURL
+				}
+			}
+			line := ""
+			if result.Line > 0 {
+				line = fmt.Sprint(result.Line)
+			}
+			section.rows = append(section.rows, fmt.Sprintf("| %s | %s | %s | %s |\n",
+				icon, line, escapeMarkdownCell(result.URL), escapeMarkdownCell(details)))
+		}
+
+		name := group.Name
+		if config.GroupBy == "" || config.GroupBy == "source" {
+			name = displaySource(name)
+		}
+		name = escapeMarkdownCell(name)
+		if invalid > 0 {
+			section.summary = fmt.Sprintf("❌ <code>%s</code> — %d of %d links broken", name, invalid, len(results))
+			section.open = true
+		} else {
+			section.summary = fmt.Sprintf("✅ <code>%s</code> — %d links", name, len(results))
+		}
+		sections = append(sections, section)
+	}
+
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].open && !sections[j].open
+	})
+
+	if len(output.Findings) > 0 {
+		section := markdownSection{
+			summary: fmt.Sprintf("⚠️ Lint findings — %d", len(output.Findings)),
+			header:  "| Rule | Location | Message |\n| --- | --- | --- |\n",
+		}
+		for _, finding := range output.Findings {
+			location := displaySource(finding.Source)
+			if finding.Line > 0 {
+				location += fmt.Sprintf(":%d", finding.Line)
+			}
+			section.rows = append(section.rows, fmt.Sprintf("| %s | %s | %s |\n",
+				finding.Rule, escapeMarkdownCell(location), escapeMarkdownCell(finding.Message)))
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	output := Output{
+		Results: []Result{
+			{URL: "https://ok.example", Status: "valid", Source: "README.md", Line: 3},
+			{URL: "https://ok.example/a|b", Status: "valid", Source: "docs/guide.md", Line: 2},
+			{URL: "https://gone.example", Status: "invalid", Error: "404 Not Found", Source: "docs/guide.md", Line: 7},
+		},
+		Findings: []Finding{{Rule: "unused-reference", Message: "Reference definition \"x\" is never used", Source: "README.md", Line: 20}},
+	}
+	output.Summary.Total, output.Summary.Valid, output.Summary.Invalid = 3, 2, 1
+
+	report := renderMarkdown(output, 0)
+	for _, want := range []string{
+		"| 3 | 2 | 1 | 0 | 1 |",
+		"<details open>\n<summary>❌ <code>docs/guide.md</code> — 1 of 2 links broken</summary>",
+		"| ❌ | 7 | https://gone.example | 404 Not Found |",
+		`https://ok.example/a\|b`,
+		"| unused-reference | README.md:20 |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Index(report, "docs/guide.md") > strings.Index(report, "README.md") {
+		t.Error("expected the source with broken links first")
+	}
+	if strings.Index(report, "gone.example") > strings.Index(report, "a\\|b") {
+		t.Error("expected broken links first within a source")
+	}
+	if strings.Contains(report, "more not shown") {
+		t.Error("unexpected truncation footer without a limit")
+	}
+}
+
+func TestRenderMarkdown_Limit(t *testing.T) {
+	var output Output
+	for i := 0; i < 200; i++ {
+		output.Results = append(output.Results, Result{
+			URL: fmt.Sprintf("https://gone.example/%d", i), Status: "invalid", Error: "404 Not Found",
+			Source: fmt.Sprintf("docs/page%02d.md", i%20), Line: i,
+		})
+	}
+
+	const limit = 4000
+	report := renderMarkdown(output, limit)
+	if len(report) > limit {
+		t.Errorf("report is %d bytes, limit is %d", len(report), limit)
+	}
+	shown := strings.Count(report, "| ❌ |")
+	if !strings.Contains(report, fmt.Sprintf("_… and %d more not shown", 200-shown)) {
+		t.Errorf("missing or wrong truncation footer (%d shown):\n%s", shown, report)
+	}
+	if strings.Count(report, "<details") != strings.Count(report, "</details>") {
+		t.Error("truncated report has unbalanced details tags")
+	}
+}
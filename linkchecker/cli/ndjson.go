@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// NDJSON lines carry a type that tells results, lint findings and the closing
+// summary apart
+type ndjsonResult struct {
+	Type string `json:"type"`
+	Result
+}
+
+type ndjsonFinding struct {
+	Type string `json:"type"`
+	Finding
+}
+
+type ndjsonSummary struct {
+	Type       string `json:"type"`
+	Total      int    `json:"total"`
+	Valid      int    `json:"valid"`
+	Invalid    int    `json:"invalid"`
+	Suppressed int    `json:"suppressed"`
+	Skipped    int    `json:"skipped"`
+	Baselined  int    `json:"baselined"`
+	Findings   int    `json:"findings"`
+	Duration   string `json:"duration"`
+}
+
+// ndjsonStream writes one JSON object per line, results first as they complete,
+// then lint findings and a summary line. It counts the results it writes itself,
+// so that it does not need the results of the run once they are streamed.
+type ndjsonStream struct {
+	encoder *json.Encoder
+	summary ndjsonSummary
+	err     error
+}
+
+func newNDJSONStream(w io.Writer) resultStream {
+	return &ndjsonStream{encoder: json.NewEncoder(w), summary: ndjsonSummary{Type: "summary"}}
+}
+
+func (s *ndjsonStream) write(record interface{}) {
+	if s.err == nil {
+		s.err = s.encoder.Encode(record)
+	}
+}
+
+func (s *ndjsonStream) Result(result Result) {
+	s.summary.Total++
+	switch result.Status {
+	case "valid":
+		s.summary.Valid++
+	case "suppressed":
+		s.summary.Suppressed++
+	case "skipped":
+		s.summary.Skipped++
+	default:
+		s.summary.Invalid++
+		if result.Baselined {
+			s.summary.Baselined++
+		}
+	}
+	s.write(ndjsonResult{Type: "result", Result: result})
+}
+
+func (s *ndjsonStream) Finish(findings []Finding, duration string) error {
+	for _, finding := range findings {
+		s.write(ndjsonFinding{Type: "finding", Finding: finding})
+	}
+	s.summary.Findings = len(findings)
+	s.summary.Duration = duration
+	s.write(s.summary)
+	return s.err
+}
+
+// outputNDJSON writes a complete output at once, for when results were not streamed
+func outputNDJSON(w io.Writer, output Output) error {
+	stream := newNDJSONStream(w)
+	for _, result := range output.Results {
+		stream.Result(result)
+	}
+	return stream.Finish(output.Findings, output.Summary.Duration)
+}
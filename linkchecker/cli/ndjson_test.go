@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestNDJSONStream(t *testing.T) {
+	var buf bytes.Buffer
+	stream := newNDJSONStream(&buf)
+
+	result := Result{URL: "https://gone.example", Status: "invalid", StatusCode: 404, Source: "README.md", Line: 7}
+	stream.Result(result)
+	if buf.Len() == 0 {
+		t.Fatal("expected the result to be written before the run finishes")
+	}
+
+	findings := []Finding{{Rule: "unused-reference", Message: "unused", Source: "README.md", Line: 9}}
+	if err := stream.Finish(findings, "1s"); err != nil {
+		t.Fatalf("Finish error: %v", err)
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+
+	if lines[0]["type"] != "result" || lines[0]["url"] != "https://gone.example" || lines[0]["source"] != "README.md" {
+		t.Errorf("unexpected result line: %v", lines[0])
+	}
+	if lines[1]["type"] != "finding" || lines[1]["rule"] != "unused-reference" || lines[1]["line"] != float64(9) {
+		t.Errorf("unexpected finding line: %v", lines[1])
+	}
+	if lines[2]["type"] != "summary" || lines[2]["total"] != float64(1) || lines[2]["invalid"] != float64(1) ||
+		lines[2]["baselined"] != float64(0) || lines[2]["findings"] != float64(1) {
+		t.Errorf("unexpected summary line: %v", lines[2])
+	}
+}
+
+func TestNDJSONStream_Baseline(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+	config = Config{}
+
+	var buf bytes.Buffer
+	stream := newNDJSONStream(&buf)
+	resultReporter = stream.Result
+	streamBaseline = Baseline{Entries: []BaselineEntry{{Source: "README.md", URL: "https://known.example"}}}.keys()
+	defer func() { resultReporter, streamBaseline = nil, nil }()
+
+	reportResult(Result{URL: "https://known.example", Status: "invalid", Source: "README.md", Line: 3})
+	reportResult(Result{URL: "https://new.example", Status: "invalid", Source: "README.md", Line: 4})
+	reportResult(Result{URL: "https://ok.example", Status: "valid", Source: "README.md", Line: 5})
+	if err := stream.Finish(nil, "1s"); err != nil {
+		t.Fatalf("Finish error: %v", err)
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(lines))
+	}
+	if lines[0]["baselined"] != true || lines[1]["baselined"] != nil {
+		t.Errorf("expected only the known link to be baselined, got %v and %v", lines[0], lines[1])
+	}
+	if summary := lines[3]; summary["total"] != float64(3) || summary["invalid"] != float64(2) || summary["baselined"] != float64(1) {
+		t.Errorf("unexpected summary line: %v", summary)
+	}
+}
+
+func TestRetainedResults(t *testing.T) {
+	defer func() { streamOnly = false }()
+	results := []Result{{Status: "valid"}, {Status: "invalid"}, {Status: "suppressed"}, {Status: "skipped"}}
+
+	if got := retainedResults(append([]Result(nil), results...)); len(got) != 4 {
+		t.Errorf("expected all results without streaming, got %+v", got)
+	}
+	streamOnly = true
+	if got := retainedResults(results); len(got) != 2 || got[0].Status != "invalid" || got[1].Status != "skipped" {
+		t.Errorf("expected only broken and skipped results while streaming, got %+v", got)
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// groupKeys map each --group-by value to the key a result is grouped under
+var groupKeys = map[string]func(Result) string{
+	"source": func(r Result) string { return r.Source },
+	"domain": func(r Result) string { return linkDomain(r.URL) },
+	"status": func(r Result) string { return r.Status },
+	// Links without an error are grouped under their status, e.g. "valid"
+	"error": func(r Result) string {
+		if r.ErrorKind != "" {
+			return r.ErrorKind
+		}
+		return r.Status
+	},
+}
+
+// sortKeys map each --sort value to a comparison; a leading "-" reverses it
+var sortKeys = map[string]func(a, b Result) int{
+	"source":      func(a, b Result) int { return 0 },
+	"url":         func(a, b Result) int { return strings.Compare(a.URL, b.URL) },
+	"domain":      func(a, b Result) int { return strings.Compare(linkDomain(a.URL), linkDomain(b.URL)) },
+	"status":      func(a, b Result) int { return strings.Compare(a.Status, b.Status) },
+	"status-code": func(a, b Result) int { return a.StatusCode - b.StatusCode },
+	"error":       func(a, b Result) int { return strings.Compare(a.ErrorKind, b.ErrorKind) },
+	"duration": func(a, b Result) int {
+		da, _ := time.ParseDuration(a.Duration)
+		db, _ := time.ParseDuration(b.Duration)
+		return int(da - db)
+	},
+}
+
+// groupKey returns the group of a result; an empty groupBy means by source
+func groupKey(groupBy string, result Result) string {
+	if key, ok := groupKeys[groupBy]; ok {
+		return key(result)
+	}
+	return result.Source
+}
+
+// validateOrder checks the --group-by and --sort values
+func validateOrder(groupBy, sortBy string) error {
+	if _, ok := groupKeys[groupBy]; !ok {
+		return fmt.Errorf("invalid group-by '%s': must be one of %s", groupBy, strings.Join(sortedNames(groupKeys), ", "))
+	}
+	if _, ok := sortKeys[strings.TrimPrefix(sortBy, "-")]; !ok {
+		return fmt.Errorf("invalid sort '%s': must be one of %s", sortBy, strings.Join(sortedNames(sortKeys), ", "))
+	}
+	return nil
+}
+
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// orderResults sorts results so that every run prints them in the same order:
+// by group, then by the sort key, then by source path, line, column and URL
+func orderResults(results []Result, groupBy, sortBy string) {
+	descending := strings.HasPrefix(sortBy, "-")
+	compare, ok := sortKeys[strings.TrimPrefix(sortBy, "-")]
+	if !ok {
+		compare = sortKeys["source"]
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if ga, gb := groupKey(groupBy, a), groupKey(groupBy, b); ga != gb {
+			return ga < gb
+		}
+		if c := compare(a, b); c != 0 {
+			return (c < 0) != descending
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.URL < b.URL
+	})
+}
+
+// orderFindings sorts lint findings by source, position and rule
+func orderFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Rule < b.Rule
+	})
+}
+
+// resultGroup is a named run of results sharing a group key
+type resultGroup struct {
+	Name    string
+	Results []Result
+}
+
+// groupResults splits results into groups in order of first appearance; for
+// ordered results these are consecutive runs
+func groupResults(groupBy string, results []Result) []resultGroup {
+	var groups []resultGroup
+	index := make(map[string]int)
+	for _, result := range results {
+		key := groupKey(groupBy, result)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, resultGroup{Name: key})
+		}
+		groups[i].Results = append(groups[i].Results, result)
+	}
+	return groups
+}
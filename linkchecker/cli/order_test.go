@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderResults(t *testing.T) {
+	input := []Result{
+		{URL: "https://b.example/x", Status: "valid", Source: "docs/b.md", Line: 4, Duration: "30ms"},
+		{URL: "https://a.example/y", Status: "invalid", ErrorKind: "not-found", Source: "docs/a.md", Line: 9, Duration: "10ms"},
+		{URL: "https://b.example/z", Status: "valid", Source: "docs/a.md", Line: 2, Duration: "50ms"},
+		{URL: "https://a.example/a", Status: "valid", Source: "docs/a.md", Line: 2, Duration: "20ms"},
+	}
+	urls := func(results []Result) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.URL)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		groupBy string
+		sortBy  string
+		want    []string
+	}{
+		{"default", "source", "source", []string{"https://a.example/a", "https://b.example/z", "https://a.example/y", "https://b.example/x"}},
+		{"by domain", "domain", "source", []string{"https://a.example/a", "https://a.example/y", "https://b.example/z", "https://b.example/x"}},
+		{"by error", "error", "source", []string{"https://a.example/y", "https://a.example/a", "https://b.example/z", "https://b.example/x"}},
+		{"slowest first", "source", "-duration", []string{"https://b.example/z", "https://a.example/a", "https://a.example/y", "https://b.example/x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := append([]Result(nil), input...)
+			orderResults(results, tt.groupBy, tt.sortBy)
+			if got := urls(results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	groups := groupResults("domain", input)
+	if len(groups) != 2 || groups[0].Name != "b.example" || len(groups[0].Results) != 2 {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	if err := validateOrder("domain", "-status-code"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateOrder("folder", "source"); err == nil {
+		t.Error("expected an error for an unknown group-by")
+	}
+	if err := validateOrder("source", "size"); err == nil {
+		t.Error("expected an error for an unknown sort")
+	}
+}
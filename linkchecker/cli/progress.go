@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressInterval is how often the progress line is redrawn
+const progressInterval = 100 * time.Millisecond
+
+// progress draws a single, continuously updated status line while links are
+// checked. All methods are safe for concurrent use and do nothing on a nil receiver.
+type progress struct {
+	w       io.Writer
+	colors  palette
+	mu      sync.Mutex
+	start   time.Time
+	total   int
+	checked int
+	// inFlight counts running checks per host
+	inFlight map[string]int
+	stopped  chan struct{}
+	done     sync.WaitGroup
+}
+
+// newProgress starts redrawing the progress line on w until stop is called
+func newProgress(w io.Writer, colors palette) *progress {
+	p := &progress{
+		w:        w,
+		colors:   colors,
+		start:    time.Now(),
+		inFlight: make(map[string]int),
+		stopped:  make(chan struct{}),
+	}
+	p.done.Add(1)
+	go func() {
+		defer p.done.Done()
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.draw()
+			case <-p.stopped:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// queue adds links that are about to be checked to the total
+func (p *progress) queue(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.total += n
+	p.mu.Unlock()
+}
+
+// begin marks a link as being checked
+func (p *progress) begin(link string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.inFlight[progressHost(link)]++
+	p.mu.Unlock()
+}
+
+// finish marks a link as checked
+func (p *progress) finish(link string) {
+	if p == nil {
+		return
+	}
+	host := progressHost(link)
+	p.mu.Lock()
+	p.checked++
+	if p.inFlight[host]--; p.inFlight[host] <= 0 {
+		delete(p.inFlight, host)
+	}
+	p.mu.Unlock()
+}
+
+// stop ends the display and clears the line so that the report starts cleanly
+func (p *progress) stop() {
+	if p == nil {
+		return
+	}
+	close(p.stopped)
+	p.done.Wait()
+	fmt.Fprint(p.w, ansiClearLine)
+}
+
+func (p *progress) draw() {
+	p.mu.Lock()
+	line := p.line(time.Now())
+	p.mu.Unlock()
+	fmt.Fprint(p.w, ansiClearLine+line)
+}
+
+// line renders the current state, e.g. "Checking links 12/40 · github.com, example.org · ETA 4s".
+// The caller must hold p.mu.
+func (p *progress) line(now time.Time) string {
+	parts := []string{fmt.Sprintf("Checking links %s/%d", p.colors.bold(fmt.Sprint(p.checked)), p.total)}
+
+	if len(p.inFlight) > 0 {
+		hosts := make([]string, 0, len(p.inFlight))
+		for host := range p.inFlight {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		const maxHosts = 3
+		shown := strings.Join(hosts[:min(len(hosts), maxHosts)], ", ")
+		if len(hosts) > maxHosts {
+			shown += fmt.Sprintf(" +%d more", len(hosts)-maxHosts)
+		}
+		parts = append(parts, p.colors.cyan(shown))
+	}
+
+	if remaining := p.total - p.checked; p.checked > 0 && remaining > 0 {
+		perLink := now.Sub(p.start) / time.Duration(p.checked)
+		eta := (perLink * time.Duration(remaining)).Round(time.Second)
+		parts = append(parts, p.colors.dim("ETA "+eta.String()))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// progressHost returns the host of a link, or "files" for local links
+func progressHost(link string) string {
+	if u, err := url.Parse(link); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "files"
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressLine(t *testing.T) {
+	start := time.Now()
+	p := &progress{start: start, inFlight: make(map[string]int)}
+
+	p.total = 10
+	for _, link := range []string{"https://d.example/1", "https://a.example/1", "https://c.example/1", "https://b.example/1", "docs/x.md"} {
+		p.inFlight[progressHost(link)]++
+	}
+	p.checked = 2
+
+	line := p.line(start.Add(4 * time.Second))
+	for _, want := range []string{"Checking links 2/10", "a.example, b.example, c.example +2 more", "ETA 16s"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("progress line %q is missing %q", line, want)
+		}
+	}
+
+	p.checked = 10
+	if line := p.line(start.Add(5 * time.Second)); strings.Contains(line, "ETA") {
+		t.Errorf("no ETA expected once all links are checked: %q", line)
+	}
+}
+
+func TestProgress_NilSafe(t *testing.T) {
+	var p *progress
+	p.queue(1)
+	p.begin("https://example.com")
+	p.finish("https://example.com")
+	p.stop()
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"bxfferoverflow.me/link-checker/linkchecker/parser"
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifFingerprintKey names the fingerprint algorithm; bump it if the hash input changes
+	sarifFingerprintKey = "linkchecker/v1"
+)
+
+// sarifLog is the root object of a SARIF 2.1.0 file
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	// BaselineState is "new" or "unchanged" when a --baseline file is used
+	BaselineState string `json:"baselineState,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifRuleDescriptions describes every rule a SARIF result can reference
+var sarifRuleDescriptions = map[string]string{
+	string(validator.ErrorNotFound):       "Linked page was not found (404/410)",
+	string(validator.ErrorHTTPClient):     "Linked page returned a client error (4xx)",
+	string(validator.ErrorHTTPServer):     "Linked page returned a server error (5xx)",
+	string(validator.ErrorTimeout):        "Request to the linked page timed out",
+	string(validator.ErrorDNS):            "Host name of the link could not be resolved",
+	string(validator.ErrorTLS):            "TLS handshake or certificate verification failed",
+	string(validator.ErrorConnection):     "Connection to the linked host failed",
+	string(validator.ErrorRequest):        "Request to the linked page failed",
+	string(validator.ErrorFileNotFound):   "Linked file does not exist",
+	string(validator.ErrorMissingIndex):   "Linked directory has no index file",
+	string(validator.ErrorAnchorNotFound): "Linked file has no matching heading or anchor",
+	string(validator.ErrorFile):           "Linked file could not be accessed",
+	parser.RuleUnusedReference:            "Reference definition is never used",
+	parser.RuleUndefinedReference:         "Reference link uses an undefined label",
+}
+
+// lintRules are the rules reported as warnings rather than errors
+var lintRules = map[string]struct{}{
+	parser.RuleUnusedReference:    {},
+	parser.RuleUndefinedReference: {},
+}
+
+// sarifRuleOrder lists rule IDs in the order they appear in the driver
+func sarifRuleOrder() []string {
+	order := make([]string, 0, len(validator.ErrorKinds)+2)
+	for _, kind := range validator.ErrorKinds {
+		order = append(order, string(kind))
+	}
+	return append(order, parser.RuleUnusedReference, parser.RuleUndefinedReference)
+}
+
+func outputSARIF(w io.Writer, output Output) error {
+	rules := make([]sarifRule, 0, len(sarifRuleDescriptions))
+	ruleIndex := make(map[string]int)
+	for _, id := range sarifRuleOrder() {
+		level := "error"
+		if _, isLint := lintRules[id]; isLint {
+			level = "warning"
+		}
+		ruleIndex[id] = len(rules)
+		rules = append(rules, sarifRule{
+			ID:                   id,
+			ShortDescription:     sarifMessage{Text: sarifRuleDescriptions[id]},
+			DefaultConfiguration: sarifConfiguration{Level: level},
+		})
+	}
+
+	results := []sarifResult{}
+	fingerprints := make(fingerprinter)
+	for _, result := range output.Results {
+		if result.Status != "invalid" {
+			continue
+		}
+		ruleID := result.ErrorKind
+		if _, ok := ruleIndex[ruleID]; !ok {
+			ruleID = string(validator.ErrorRequest)
+		}
+
+		message := fmt.Sprintf("Broken link %s", result.URL)
+		if result.Error != "" {
+			message += ": " + result.Error
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndex[ruleID],
+			Level:     "error",
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{sarifLocationFor(result.Source, result.Line, result.Column)},
+			PartialFingerprints: map[string]string{
+				sarifFingerprintKey: fingerprints.next(ruleID, result.Source, result.URL),
+			},
+			BaselineState: sarifBaselineState(result),
+		})
+	}
+
+	for _, finding := range output.Findings {
+		results = append(results, sarifResult{
+			RuleID:    finding.Rule,
+			RuleIndex: ruleIndex[finding.Rule],
+			Level:     "warning",
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{sarifLocationFor(finding.Source, finding.Line, finding.Column)},
+			PartialFingerprints: map[string]string{
+				sarifFingerprintKey: fingerprints.next(finding.Rule, finding.Source, finding.Message),
+			},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:    "linkchecker",
+				Version: versionInfo.version,
+				Rules:   rules,
+			}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifLocationFor builds a location for a local file or a checked web page
+func sarifLocationFor(source string, line, column int) sarifLocation {
+	location := sarifLocation{}
+	if isURL(source) {
+		location.PhysicalLocation.ArtifactLocation.URI = source
+	} else {
+		location.PhysicalLocation.ArtifactLocation = sarifArtifactLocation{
+			URI:       relativeSourcePath(source),
+			URIBaseID: "%SRCROOT%",
+		}
+	}
+	if line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: line, StartColumn: column}
+	}
+	return location
+}
+
+// sarifBaselineState tells code scanning whether a broken link is known from the baseline
+func sarifBaselineState(result Result) string {
+	switch {
+	case config.Baseline == "":
+		return ""
+	case result.Baselined:
+		return "unchanged"
+	default:
+		return "new"
+	}
+}
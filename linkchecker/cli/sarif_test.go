@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestOutputSARIF(t *testing.T) {
+	output := Output{
+		Results: []Result{
+			{URL: "https://ok.example", Status: "valid", StatusCode: 200, Source: "docs/guide.md", Line: 3, Column: 5},
+			{URL: "https://gone.example", Status: "invalid", StatusCode: 404, Error: "404 Not Found",
+				ErrorKind: "not-found", Source: "docs/guide.md", Line: 7, Column: 12},
+		},
+		Findings: []Finding{
+			{Rule: "unused-reference", Message: "reference definition [x] is never used", Source: "docs/guide.md", Line: 9, Column: 6},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := outputSARIF(&buf, output); err != nil {
+		t.Fatalf("outputSARIF error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF header: version=%s runs=%d", log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(run.Results))
+	}
+
+	broken := run.Results[0]
+	if broken.RuleID != "not-found" || broken.Level != "error" {
+		t.Errorf("unexpected rule or level: %s/%s", broken.RuleID, broken.Level)
+	}
+	if run.Tool.Driver.Rules[broken.RuleIndex].ID != broken.RuleID {
+		t.Errorf("rule index %d does not point to %s", broken.RuleIndex, broken.RuleID)
+	}
+	location := broken.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "docs/guide.md" || location.Region.StartLine != 7 || location.Region.StartColumn != 12 {
+		t.Errorf("unexpected location: %+v %+v", location.ArtifactLocation, location.Region)
+	}
+	if lint := run.Results[1]; lint.RuleID != "unused-reference" || lint.Level != "warning" {
+		t.Errorf("unexpected lint result: %s/%s", lint.RuleID, lint.Level)
+	}
+
+	// Moving the link to another line must not change its fingerprint
+	output.Results[1].Line = 42
+	buf.Reset()
+	if err := outputSARIF(&buf, output); err != nil {
+		t.Fatalf("outputSARIF error: %v", err)
+	}
+	var moved sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &moved); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if moved.Runs[0].Results[0].PartialFingerprints[sarifFingerprintKey] != broken.PartialFingerprints[sarifFingerprintKey] {
+		t.Errorf("fingerprint changed when the line changed")
+	}
+}
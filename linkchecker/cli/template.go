@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// templateData is what a user template is executed against. It embeds Output,
+// so .Results, .Findings and .Summary work as in the JSON output.
+type templateData struct {
+	Output
+	// Sources groups the results by source
+	Sources []resultGroup
+	// Groups groups the results as selected with --group-by
+	Groups []resultGroup
+}
+
+// templateFuncs are the helpers available in user templates
+var templateFuncs = template.FuncMap{
+	"groupBy": templateGroupBy,
+	"sortBy":  templateSortBy,
+	"where":   templateWhere,
+	"count":   templateCount,
+	"relPath": displaySource,
+	"domain":  linkDomain,
+	"add":     func(a, b int) int { return a + b },
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"repeat":  strings.Repeat,
+	"replace": strings.ReplaceAll,
+	"pad": func(width int, s string) string {
+		return fmt.Sprintf("%-*s", width, s)
+	},
+}
+
+// loadTemplate parses a user template file with the helper functions
+func loadTemplate(path string) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// outputTemplate renders the output with the template given by --template
+func outputTemplate(w io.Writer, output Output) error {
+	tmpl, err := loadTemplate(config.Template)
+	if err != nil {
+		return err
+	}
+	return executeTemplate(w, tmpl, output)
+}
+
+func executeTemplate(w io.Writer, tmpl *template.Template, output Output) error {
+	data := templateData{
+		Output:  output,
+		Sources: groupResults("source", output.Results),
+		Groups:  groupResults(config.GroupBy, output.Results),
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("error executing template: %w", err)
+	}
+	return nil
+}
+
+// resultField returns a result field by its JSON name; line and status_code are ints
+func resultField(field string, result Result) (interface{}, error) {
+	switch field {
+	case "source":
+		return result.Source, nil
+	case "url":
+		return result.URL, nil
+	case "status":
+		return result.Status, nil
+	case "error_kind":
+		return result.ErrorKind, nil
+	case "error":
+		return result.Error, nil
+	case "domain":
+		return linkDomain(result.URL), nil
+	case "line":
+		return result.Line, nil
+	case "status_code":
+		return result.StatusCode, nil
+	default:
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+}
+
+// templateGroupBy groups results by a field, keeping the order of first appearance
+func templateGroupBy(field string, results []Result) ([]resultGroup, error) {
+	var groups []resultGroup
+	index := make(map[string]int)
+	for _, result := range results {
+		value, err := resultField(field, result)
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprint(value)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, resultGroup{Name: key})
+		}
+		groups[i].Results = append(groups[i].Results, result)
+	}
+	return groups, nil
+}
+
+// templateSortBy returns a copy of results sorted by comma-separated fields
+func templateSortBy(fields string, results []Result) ([]Result, error) {
+	keys := strings.Split(fields, ",")
+	for i, key := range keys {
+		keys[i] = strings.TrimSpace(key)
+		if _, err := resultField(keys[i], Result{}); err != nil {
+			return nil, err
+		}
+	}
+	sorted := append([]Result(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, key := range keys {
+			a, _ := resultField(key, sorted[i])
+			b, _ := resultField(key, sorted[j])
+			if a == b {
+				continue
+			}
+			if n, ok := a.(int); ok {
+				return n < b.(int)
+			}
+			return a.(string) < b.(string)
+		}
+		return false
+	})
+	return sorted, nil
+}
+
+// templateWhere keeps the results whose field equals value
+func templateWhere(field, value string, results []Result) ([]Result, error) {
+	var matched []Result
+	for _, result := range results {
+		v, err := resultField(field, result)
+		if err != nil {
+			return nil, err
+		}
+		if fmt.Sprint(v) == value {
+			matched = append(matched, result)
+		}
+	}
+	return matched, nil
+}
+
+// templateCount counts the results with the given status
+func templateCount(status string, results []Result) int {
+	matched, _ := templateWhere("status", status, results)
+	return len(matched)
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputTemplate(t *testing.T) {
+	output := Output{
+		Results: []Result{
+			{URL: "https://b.example", Status: "invalid", StatusCode: 404, Source: "README.md", Line: 9},
+			{URL: "https://a.example", Status: "valid", StatusCode: 200, Source: "README.md", Line: 3},
+			{URL: "https://c.example", Status: "invalid", StatusCode: 500, Source: "docs/guide.md", Line: 2},
+		},
+	}
+	output.Summary.Total, output.Summary.Valid, output.Summary.Invalid = 3, 1, 2
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "summary",
+			template: `{{.Summary.Invalid}}/{{.Summary.Total}} broken`,
+			want:     "2/3 broken",
+		},
+		{
+			name:     "sources",
+			template: `{{range .Sources}}{{relPath .Name}}:{{count "invalid" .Results}} {{end}}`,
+			want:     "README.md:1 docs/guide.md:1 ",
+		},
+		{
+			name:     "sortBy",
+			template: `{{range sortBy "source,line" .Results}}{{.Line}} {{end}}`,
+			want:     "3 9 2 ",
+		},
+		{
+			name:     "groupBy and where",
+			template: `{{range groupBy "status_code" (where "status" "invalid" .Results)}}{{.Name}}={{domain (index .Results 0).URL}} {{end}}`,
+			want:     "404=b.example 500=c.example ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.tmpl")
+			if err := os.WriteFile(path, []byte(tt.template), 0644); err != nil {
+				t.Fatal(err)
+			}
+			tmpl, err := loadTemplate(path)
+			if err != nil {
+				t.Fatalf("loadTemplate error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := executeTemplate(&buf, tmpl, output); err != nil {
+				t.Fatalf("executeTemplate error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputTemplate_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(path, []byte(`{{range sortBy "colour" .Results}}{{end}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := loadTemplate(path)
+	if err != nil {
+		t.Fatalf("loadTemplate error: %v", err)
+	}
+	if err := executeTemplate(&bytes.Buffer{}, tmpl, Output{}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
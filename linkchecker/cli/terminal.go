@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ANSI escape sequences used by the terminal renderer
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiDim    = "\033[2m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+	// ansiClearLine moves to the start of the line and erases it
+	ansiClearLine = "\r\033[K"
+	// ansiClearScreen moves to the top left corner and erases the screen
+	ansiClearScreen = "\033[H\033[2J"
+)
+
+// isTerminal reports whether w is a character device such as a terminal
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled decides whether output written to w is colored. In auto mode
+// color is used for terminals only, and never when NO_COLOR is set or TERM is dumb.
+func colorEnabled(mode string, w io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if _, set := os.LookupEnv("NO_COLOR"); set || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// validateColor checks a --color value
+func validateColor(mode string) error {
+	if mode != "auto" && mode != "always" && mode != "never" {
+		return fmt.Errorf("invalid color '%s': must be 'auto', 'always' or 'never'", mode)
+	}
+	return nil
+}
+
+// palette wraps text in color codes when enabled
+type palette bool
+
+func (p palette) paint(code, s string) string {
+	if !p || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+func (p palette) bold(s string) string   { return p.paint(ansiBold, s) }
+func (p palette) dim(s string) string    { return p.paint(ansiDim, s) }
+func (p palette) red(s string) string    { return p.paint(ansiRed, s) }
+func (p palette) green(s string) string  { return p.paint(ansiGreen, s) }
+func (p palette) yellow(s string) string { return p.paint(ansiYellow, s) }
+func (p palette) cyan(s string) string   { return p.paint(ansiCyan, s) }
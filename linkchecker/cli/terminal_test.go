@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	if !colorEnabled("always", &buf) {
+		t.Error("expected color with --color=always")
+	}
+	if colorEnabled("never", &buf) {
+		t.Error("expected no color with --color=never")
+	}
+	if colorEnabled("auto", &buf) {
+		t.Error("expected no color for a non-terminal writer")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled("auto", &buf) {
+		t.Error("expected NO_COLOR to disable color")
+	}
+}
+
+func TestOutputText_Levels(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	output := Output{Results: []Result{
+		{URL: "https://ok.example", Status: "valid", Source: "README.md", Line: 3, Duration: "12ms"},
+		{URL: "https://gone.example", Status: "invalid", Error: "404 Not Found", Source: "README.md", Line: 7},
+		{URL: "https://fine.example", Status: "valid", Source: "docs/guide.md", Line: 1},
+	}}
+
+	config = Config{Quiet: true, Color: "never"}
+	var quiet bytes.Buffer
+	if err := outputText(&quiet, output); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(quiet.String(), "ok.example") || strings.Contains(quiet.String(), "docs/guide.md") {
+		t.Errorf("quiet output should only list broken links:\n%s", quiet.String())
+	}
+	if !strings.Contains(quiet.String(), "✗ https://gone.example") {
+		t.Errorf("quiet output is missing the broken link:\n%s", quiet.String())
+	}
+
+	config = Config{Verbose: true, Color: "always"}
+	var verbose bytes.Buffer
+	if err := outputText(&verbose, output); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(verbose.String(), "Duration: "+ansiDim+"12ms") {
+		t.Errorf("verbose output should show colored durations:\n%q", verbose.String())
+	}
+	if !strings.Contains(verbose.String(), ansiRed+"✗"+ansiReset) {
+		t.Error("expected broken links to be marked in red")
+	}
+}
+
+func TestOutputText_File(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	output := Output{Results: []Result{
+		{URL: "https://ok.example", Status: "valid", Source: "README.md", Line: 3},
+		{URL: "https://gone.example", Status: "invalid", Error: "404 Not Found", Source: "README.md", Line: 7},
+	}}
+	path := filepath.Join(t.TempDir(), "links.txt")
+	config = Config{Quiet: true, Color: "always", Outputs: []OutputTarget{{Format: "text", Path: path}}}
+	if err := writeOutputTargets(output); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	if !strings.Contains(report, "✓ https://ok.example") || !strings.Contains(report, "✗ https://gone.example") {
+		t.Errorf("a text report file should list every result despite --quiet:\n%s", report)
+	}
+	if strings.Contains(report, "\x1b[") {
+		t.Errorf("a text report file should not be colored:\n%q", report)
+	}
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ignoreFileNames are the files whose patterns exclude paths from directory walks
+var ignoreFileNames = []string{".gitignore", ".ignore"}
+
+// vendorDirs hold third-party files and are skipped unless --no-ignore is given
+var vendorDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// ignoreRule is one pattern of a .gitignore or .ignore file
+type ignoreRule struct {
+	// dir is the absolute directory of the ignore file; patterns are relative to it
+	dir     string
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// parseIgnoreFile converts the gitignore patterns of a file in dir into doublestar rules
+func parseIgnoreFile(dir string, data []byte) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{dir: dir}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			// \# and \! start patterns with a literal character
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		// Patterns with a slash are anchored to the ignore file, others match at any depth
+		if strings.Contains(line, "/") {
+			rule.pattern = strings.TrimPrefix(line, "/")
+		} else {
+			rule.pattern = "**/" + line
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// matches reports whether the rule applies to an absolute path
+func (r ignoreRule) matches(path string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel, err := filepath.Rel(r.dir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	ok, _ := doublestar.Match(r.pattern, filepath.ToSlash(rel))
+	return ok
+}
+
+// isIgnored applies the rules in order; the last matching rule decides
+func isIgnored(rules []ignoreRule, path string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.matches(path, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// loadIgnoreRules reads the ignore files of a directory
+func loadIgnoreRules(dir string) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	for _, name := range ignoreFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, parseIgnoreFile(dir, data)...)
+	}
+	return rules, nil
+}
+
+// ancestorIgnoreRules reads .git/info/exclude and the ignore files above dir up to the
+// root of its Git repository. Outside a repository, only ignore files within dir apply.
+func ancestorIgnoreRules(dir string) ([]ignoreRule, error) {
+	var ancestors []string
+	current := dir
+	for !isRepositoryRoot(current) {
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, nil
+		}
+		current = parent
+		ancestors = append([]string{current}, ancestors...)
+	}
+
+	// Local excludes of the repository come first, so that ignore files override them
+	var rules []ignoreRule
+	if data, err := os.ReadFile(filepath.Join(current, ".git", "info", "exclude")); err == nil {
+		rules = parseIgnoreFile(current, data)
+	}
+	for _, ancestor := range ancestors {
+		dirRules, err := loadIgnoreRules(ancestor)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, dirRules...)
+	}
+	return rules, nil
+}
+
+// isRepositoryRoot reports whether dir contains a .git directory or file
+func isRepositoryRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// matchesAny reports whether a slash-separated path matches one of the glob patterns
+func matchesAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := doublestar.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGlobs checks the --include and --exclude patterns
+func validateGlobs() error {
+	for _, pattern := range append(append([]string{}, config.Include...), config.Exclude...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("invalid glob pattern '%s'", pattern)
+		}
+	}
+	return nil
+}
+
+// walkFiles calls fn for every supported file of an input path. A file given directly
+// is always used. Directories are only descended into with --recursive, and hidden
+// entries, vendor directories, ignored paths and paths filtered by --include and
+// --exclude are skipped.
+func walkFiles(inputPath string, fn func(path string) error) error {
+	return walkInput(inputPath, nil, fn)
+}
+
+// walkInput is walkFiles that also calls onDir for every directory it descends into,
+// including the input directory itself
+func walkInput(inputPath string, onDir, fn func(path string) error) error {
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return fmt.Errorf("path does not exist: %s", inputPath)
+	}
+	if !info.IsDir() {
+		if isSupportedFile(inputPath) {
+			return fn(inputPath)
+		}
+		return nil
+	}
+
+	root, err := filepath.Abs(inputPath)
+	if err != nil {
+		return err
+	}
+	var rules []ignoreRule
+	if !config.NoIgnore {
+		if rules, err = ancestorIgnoreRules(root); err != nil {
+			return err
+		}
+	}
+
+	return filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(inputPath, path)
+		if err != nil {
+			return err
+		}
+		abs := filepath.Join(root, rel)
+		if rel == "." {
+			return enterDir(&rules, abs, path, onDir)
+		}
+
+		name := d.Name()
+		// Include and exclude patterns are relative to the input directory
+		rel = filepath.ToSlash(rel)
+		hidden := strings.HasPrefix(name, ".") && !config.Hidden
+
+		if d.IsDir() {
+			switch {
+			case !config.Recursive:
+				logger.Debug("skipped directory", "path", path, "reason", "not recursive")
+			case name == ".git", hidden:
+				logger.Debug("skipped directory", "path", path, "reason", "hidden")
+			case vendorDirs[name] && !config.NoIgnore:
+				logger.Debug("skipped directory", "path", path, "reason", "vendor")
+			case matchesAny(config.Exclude, rel):
+				logger.Debug("skipped directory", "path", path, "reason", "excluded")
+			case isIgnored(rules, abs, true):
+				logger.Debug("skipped directory", "path", path, "reason", "ignored")
+			default:
+				return enterDir(&rules, abs, path, onDir)
+			}
+			return filepath.SkipDir
+		}
+
+		if !isSupportedFile(path) || hidden || matchesAny(config.Exclude, rel) ||
+			(len(config.Include) > 0 && !matchesAny(config.Include, rel)) || isIgnored(rules, abs, false) {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+// enterDir appends the ignore rules of a walked directory and calls onDir. Rules only
+// apply below their directory, so rules of sibling directories never match.
+func enterDir(rules *[]ignoreRule, dir, path string, onDir func(path string) error) error {
+	if onDir != nil {
+		if err := onDir(path); err != nil {
+			return err
+		}
+	}
+	if config.NoIgnore {
+		return nil
+	}
+	dirRules, err := loadIgnoreRules(dir)
+	if err != nil {
+		return err
+	}
+	*rules = append(*rules, dirRules...)
+	return nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWalkFiles(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		".gitignore":                 "build/\n*.tmp.md\n/root-only.md\n",
+		"README.md":                  "",
+		"root-only.md":               "",
+		"notes.txt":                  "",
+		"docs/guide.md":              "",
+		"docs/root-only.md":          "",
+		"docs/scratch.tmp.md":        "",
+		"docs/.ignore":               "private/\n!keep.tmp.md\n",
+		"docs/keep.tmp.md":           "",
+		"docs/private/secret.md":     "",
+		"docs/drafts/wip.md":         "",
+		"docs/site/index.html":       "",
+		"build/out.md":               "",
+		"node_modules/pkg/README.md": "",
+		"vendor/lib/README.md":       "",
+		".github/CONTRIBUTING.md":    "",
+		".hidden.md":                 "",
+	}
+	for name, content := range files {
+		writeTestFile(t, dir, name, content)
+	}
+
+	walk := func() []string {
+		t.Helper()
+		var found []string
+		err := walkFiles(dir, func(path string) error {
+			rel, err := filepath.Rel(dir, path)
+			found = append(found, filepath.ToSlash(rel))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return found
+	}
+	check := func(name string, want []string) {
+		t.Helper()
+		if got := walk(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\n got: %v\nwant: %v", name, got, want)
+		}
+	}
+
+	config = Config{}
+	check("not recursive", []string{"README.md"})
+
+	config.Recursive = true
+	check("recursive", []string{
+		"README.md", "docs/drafts/wip.md", "docs/guide.md", "docs/keep.tmp.md", "docs/root-only.md", "docs/site/index.html",
+	})
+
+	config.Exclude = []string{"docs/drafts/**", "**/*.html"}
+	config.Include = []string{"docs/**"}
+	config.NoIgnore = true
+	check("globs without ignore files", []string{
+		"docs/guide.md", "docs/keep.tmp.md", "docs/private/secret.md", "docs/root-only.md", "docs/scratch.tmp.md",
+	})
+
+	config = Config{Recursive: true, Hidden: true, Include: []string{"**/CONTRIBUTING.md", ".hidden.md"}}
+	check("hidden", []string{".github/CONTRIBUTING.md", ".hidden.md"})
+
+	// A file given directly is always checked
+	var direct []string
+	if err := walkFiles(filepath.Join(dir, "build", "out.md"), func(path string) error {
+		direct = append(direct, path)
+		return nil
+	}); err != nil || len(direct) != 1 {
+		t.Errorf("expected the ignored file given directly to be walked, got %v (%v)", direct, err)
+	}
+}
+
+func TestValidateGlobs(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+
+	config.Include = []string{"docs/**/*.md"}
+	config.Exclude = []string{"{a,b}/*"}
+	if err := validateGlobs(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	config.Exclude = []string{"docs/[.md"}
+	if err := validateGlobs(); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
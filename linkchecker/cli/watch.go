@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"bxfferoverflow.me/link-checker/linkchecker/validator"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long a watch session waits for further changes before re-checking
+const watchDebounce = 200 * time.Millisecond
+
+// resultCache keeps HTTP results between the checks of a watch session; nil otherwise
+var resultCache *validator.Cache
+
+// fileReport holds the results of one checked file
+type fileReport struct {
+	results  []Result
+	findings []Finding
+}
+
+// watchSession re-checks the files of the input paths whenever they change
+type watchSession struct {
+	w       io.Writer
+	watcher *fsnotify.Watcher
+	// files maps every discovered file to the site root of its input path
+	files   map[string]string
+	reports map[string]fileReport
+	watched map[string]bool
+}
+
+// runWatch checks all input paths, then re-checks files as they change until interrupted
+func runWatch() error {
+	if len(config.InputURLs) > 0 {
+		return fmt.Errorf("--watch only watches local files, not URLs")
+	}
+	if stdoutFormat() != "text" {
+		return fmt.Errorf("--watch requires the text format")
+	}
+	if config.CacheTTL <= 0 {
+		return fmt.Errorf("invalid cache TTL %v: must be positive", config.CacheTTL)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot watch files: %w", err)
+	}
+	defer watcher.Close()
+
+	resultCache = validator.NewCache(config.CacheTTL)
+	defer func() { resultCache = nil }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return newWatchSession(os.Stdout, watcher).run(ctx)
+}
+
+func newWatchSession(w io.Writer, watcher *fsnotify.Watcher) *watchSession {
+	return &watchSession{
+		w:       w,
+		watcher: watcher,
+		reports: make(map[string]fileReport),
+		watched: make(map[string]bool),
+	}
+}
+
+// run checks every file once and then handles file events until ctx is done
+func (s *watchSession) run(ctx context.Context) error {
+	start := time.Now()
+	checkedSources = make(map[string]bool)
+	if err := s.discover(); err != nil {
+		return err
+	}
+	startProgress(false)
+	for path := range s.files {
+		s.check(path)
+	}
+	stopProgress()
+	if err := s.render(start); err != nil {
+		return err
+	}
+
+	// Editors often write a file in several steps, so changes are collected briefly
+	changed := make(map[string]bool)
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			path := filepath.Clean(event.Name)
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				// Removed directories are no longer watched and must be added again if recreated
+				delete(s.watched, path)
+			}
+			logger.Debug("file changed", "path", path, "op", event.Op.String())
+			changed[path] = true
+			debounce = time.After(watchDebounce)
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warn("error watching files", "error", err)
+		case <-debounce:
+			debounce = nil
+			if err := s.update(changed); err != nil {
+				return err
+			}
+			changed = make(map[string]bool)
+		}
+	}
+}
+
+// update re-checks changed and new files and drops the results of removed files
+func (s *watchSession) update(changed map[string]bool) error {
+	start := time.Now()
+	if err := s.discover(); err != nil {
+		return err
+	}
+	for path := range s.reports {
+		if _, ok := s.files[path]; !ok {
+			delete(s.reports, path)
+			delete(checkedSources, displaySource(path))
+		}
+	}
+	for path := range s.files {
+		if _, checked := s.reports[path]; changed[path] || !checked {
+			s.check(path)
+		}
+	}
+	return s.render(start)
+}
+
+// discover finds the files of the input paths and watches their directories
+func (s *watchSession) discover() error {
+	files := make(map[string]string)
+	for _, inputPath := range config.InputPaths {
+		info, err := os.Stat(inputPath)
+		if err != nil {
+			logger.Warn("input path does not exist", "path", inputPath)
+			continue
+		}
+		siteRoot := siteRootFor(inputPath, info)
+		// A file is watched through its directory, so that it can be replaced
+		if !info.IsDir() {
+			if err := s.watch(filepath.Dir(inputPath)); err != nil {
+				return err
+			}
+		}
+		err = walkInput(inputPath, s.watch, func(path string) error {
+			if isSelected(path) {
+				files[filepath.Clean(path)] = siteRoot
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	s.files = files
+	return nil
+}
+
+// watch adds a directory to the watcher unless it is watched already
+func (s *watchSession) watch(dir string) error {
+	dir = filepath.Clean(dir)
+	if s.watched[dir] {
+		return nil
+	}
+	if err := s.watcher.Add(dir); err != nil {
+		return fmt.Errorf("cannot watch %s: %w", dir, err)
+	}
+	s.watched[dir] = true
+	return nil
+}
+
+// check runs the checks of one file and keeps its results
+func (s *watchSession) check(path string) {
+	results, findings, err := processFile(path, s.files[path])
+	if err != nil {
+		// The file may be removed or half written; it is checked again on its next change
+		logger.Warn("cannot check file", "source", path, "error", err)
+		delete(s.reports, path)
+		return
+	}
+	s.reports[path] = fileReport{results: results, findings: findings}
+}
+
+// render redraws the results of all files in place of the previous ones
+func (s *watchSession) render(start time.Time) error {
+	output := Output{Results: []Result{}}
+	for _, report := range s.reports {
+		output.Results = append(output.Results, report.results...)
+		output.Findings = append(output.Findings, report.findings...)
+	}
+	orderResults(output.Results, config.GroupBy, config.SortBy)
+	orderFindings(output.Findings)
+	if err := finishOutput(&output, start); err != nil {
+		return err
+	}
+	if err := writeOutputTargets(output); err != nil {
+		return err
+	}
+
+	if isTerminal(s.w) {
+		fmt.Fprint(s.w, ansiClearScreen)
+	}
+	if err := outputText(s.w, output); err != nil {
+		return err
+	}
+	colors := palette(colorEnabled(config.Color, s.w))
+	fmt.Fprintf(s.w, "\n%s\n", colors.dim(fmt.Sprintf("Watching %d files for changes, press Ctrl+C to stop", len(s.files))))
+	return nil
+}
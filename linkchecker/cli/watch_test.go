@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// syncBuffer is a bytes.Buffer that can be read while the watch session writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchSession(t *testing.T) {
+	defer func(saved Config) { config = saved }(config)
+	config = Config{InputPaths: []string{t.TempDir()}, Recursive: true, Workers: 2, Timeout: time.Second, Color: "never", Format: "text"}
+	dir := config.InputPaths[0]
+
+	writeTestFile(t, dir, "a.md", "[broken](missing.md)\n")
+	writeTestFile(t, dir, "b.md", "[ok](a.md)\n")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	var out syncBuffer
+	session := newWatchSession(&out, watcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- session.run(ctx) }()
+
+	// waitFor waits until the latest rendering contains all of want
+	waitFor := func(want ...string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			renders := strings.Split(out.String(), "Link Check Results")
+			last := renders[len(renders)-1]
+			found := true
+			for _, s := range want {
+				found = found && strings.Contains(last, s)
+			}
+			if found && strings.Contains(last, "Watching") {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %q in:\n%s", want, out.String())
+	}
+
+	waitFor("Total Links: 2", "Invalid: 1", "Watching 2 files")
+
+	writeTestFile(t, dir, "a.md", "[fixed](b.md)\n")
+	waitFor("Total Links: 2", "Invalid: 0")
+
+	// New files in new directories are picked up
+	writeTestFile(t, dir, "sub/c.md", "[gone](nothing.md)\n")
+	waitFor("Total Links: 3", "Invalid: 1", "Watching 3 files")
+
+	if err := os.Remove(filepath.Join(dir, "sub", "c.md")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("Total Links: 2", "Invalid: 0", "Watching 2 files")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
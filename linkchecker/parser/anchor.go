@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// headingAnchors vergibt die IDs der Überschriften eines Dokuments. Wiederholte IDs
+// erhalten wie auf GitHub die Endungen -1, -2 usw.
+type headingAnchors map[string]int
+
+// anchor liefert die ID der nächsten Überschrift mit dem angegebenen Text.
+func (h headingAnchors) anchor(text string) string {
+	slug := headingSlug(text)
+	count, seen := h[slug]
+	h[slug] = count + 1
+	if !seen {
+		return slug
+	}
+	return fmt.Sprintf("%s-%d", slug, count)
+}
+
+// headingSlug erzeugt die ID einer Überschrift wie GitHub: Kleinbuchstaben, ohne
+// Satzzeichen, Leerzeichen werden zu Bindestrichen.
+func headingSlug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// headingText liefert den Text einer Überschrift ohne Markdown-Auszeichnung.
+func headingText(heading *ast.Heading, source []byte) string {
+	var b strings.Builder
+	//nolint:errcheck // ast.Walk error is not relevant for collecting text
+	ast.Walk(heading, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			b.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
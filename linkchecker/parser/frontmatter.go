@@ -0,0 +1,272 @@
+package parser
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// KindFrontMatter ist ein Link aus dem Front Matter einer Seite.
+const KindFrontMatter LinkKind = "front-matter"
+
+// DefaultFrontMatterKeys sind die Front-Matter-Felder, die standardmäßig als Links gelten.
+var DefaultFrontMatterKeys = []string{"canonical", "source_url", "image", "aliases"}
+
+// frontMatter beschreibt einen Front-Matter-Block am Anfang einer Datei.
+type frontMatter struct {
+	format string // "yaml" oder "toml"
+	// start und end begrenzen den Inhalt zwischen den Trennzeilen
+	start, end int
+	// stop ist das Ende der schließenden Trennzeile
+	stop int
+}
+
+// findFrontMatter erkennt YAML- (---) und TOML-Front-Matter (+++) am Dateianfang.
+func findFrontMatter(content []byte) (frontMatter, bool) {
+	var format string
+	var closers []string
+	switch {
+	case hasDelimiterLine(content, "---"):
+		format, closers = "yaml", []string{"---", "..."}
+	case hasDelimiterLine(content, "+++"):
+		format, closers = "toml", []string{"+++"}
+	default:
+		return frontMatter{}, false
+	}
+
+	start := bytes.IndexByte(content, '\n') + 1
+	for pos := start; pos < len(content); {
+		lineEnd := bytes.IndexByte(content[pos:], '\n')
+		next := len(content)
+		if lineEnd >= 0 {
+			next = pos + lineEnd + 1
+			lineEnd += pos
+		} else {
+			lineEnd = len(content)
+		}
+		line := strings.TrimRight(string(content[pos:lineEnd]), " \t\r")
+		for _, closer := range closers {
+			if line == closer {
+				return frontMatter{format: format, start: start, end: pos, stop: next}, true
+			}
+		}
+		pos = next
+	}
+	return frontMatter{}, false
+}
+
+// hasDelimiterLine prüft, ob die erste Zeile genau aus dem Trenner besteht.
+func hasDelimiterLine(content []byte, delimiter string) bool {
+	line := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		line = content[:i]
+	}
+	return strings.TrimRight(string(line), " \t\r") == delimiter
+}
+
+// maskFrontMatter ersetzt den Front Matter durch Leerzeichen, damit er nicht als
+// Markdown geparst wird. Zeilenumbrüche bleiben erhalten, ebenso alle Offsets.
+func maskFrontMatter(content []byte, fm frontMatter) []byte {
+	masked := make([]byte, len(content))
+	copy(masked, content)
+	for i := 0; i < fm.stop; i++ {
+		if masked[i] != '\n' {
+			masked[i] = ' '
+		}
+	}
+	return masked
+}
+
+// extractFrontMatterLinks liefert die Werte aller Felder, deren Schlüsselpfad zu einem
+// der Muster passt. Verschachtelte Felder werden mit Punkten verbunden (params.image).
+func extractFrontMatterLinks(content []byte, fm frontMatter, keys []string) []Link {
+	if len(keys) == 0 {
+		return nil
+	}
+	if fm.format == "toml" {
+		return extractTOMLLinks(content, fm, keys)
+	}
+	return extractYAMLLinks(content, fm, keys)
+}
+
+func extractYAMLLinks(content []byte, fm frontMatter, keys []string) []Link {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content[fm.start:fm.end], &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+
+	// Zeilennummern von yaml.v3 beziehen sich auf den Inhalt des Blocks
+	idx := newLineIndex(content)
+	firstLine, _ := idx.position(fm.start)
+
+	var links []Link
+	var walk func(n *yaml.Node, key string)
+	walk = func(n *yaml.Node, key string) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				walk(n.Content[i+1], joinKey(key, n.Content[i].Value))
+			}
+		case yaml.SequenceNode:
+			for _, item := range n.Content {
+				walk(item, key)
+			}
+		case yaml.ScalarNode:
+			if n.Tag != "!!str" || n.Value == "" || !matchesKey(key, keys) {
+				return
+			}
+			lineStart := idx.starts[firstLine-1+n.Line-1]
+			start := lineStart + runeOffset(content[lineStart:], n.Column-1)
+			if n.Style == yaml.DoubleQuotedStyle || n.Style == yaml.SingleQuotedStyle {
+				start++
+			}
+			link := Link{URL: n.Value, Kind: KindFrontMatter, Start: start}
+			if bytes.HasPrefix(content[start:], []byte(n.Value)) {
+				link.End = start + len(n.Value)
+			}
+			links = append(links, link)
+		}
+	}
+	walk(root.Content[0], "")
+	return links
+}
+
+func extractTOMLLinks(content []byte, fm frontMatter, keys []string) []Link {
+	block := content[fm.start:fm.end]
+	var data map[string]interface{}
+	md, err := toml.Decode(string(block), &data)
+	if err != nil {
+		return nil
+	}
+
+	// TOML liefert keine Positionen, daher werden die Werte im Quelltext gesucht.
+	// Die Schlüssel kommen in der Reihenfolge des Dokuments, gesucht wird jeweils
+	// ab der Zeile "key = ..." und hinter dem zuletzt gefundenen Wert.
+	var links []Link
+	tables := make(map[string]int) // Anzahl der bisherigen [[Tabellen]] je Pfad
+	from := 0
+	for _, key := range md.Keys() {
+		name := key.String()
+		switch md.Type(key...) {
+		case "ArrayHash":
+			tables[name]++
+			continue
+		case "Hash":
+			continue
+		}
+
+		var values []string
+		collectTOMLStrings(lookupTOMLKey(data, key, tables), name, keys, &values)
+		if len(values) == 0 {
+			continue
+		}
+		if at := findTOMLKey(block[from:], key[len(key)-1]); at >= 0 {
+			from += at
+		}
+		for _, value := range values {
+			link := Link{URL: value, Kind: KindFrontMatter, Start: fm.start}
+			if i := bytes.Index(block[from:], []byte(value)); i >= 0 {
+				link.Start = fm.start + from + i
+				link.End = link.Start + len(value)
+				from += i + len(value)
+			}
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// lookupTOMLKey liefert den Wert eines Schlüsselpfads. Bei [[Tabellen]] wird die
+// zuletzt begonnene Tabelle verwendet.
+func lookupTOMLKey(data map[string]interface{}, key toml.Key, tables map[string]int) interface{} {
+	var v interface{} = data
+	for i, part := range key {
+		if list, ok := v.([]map[string]interface{}); ok {
+			n := tables[key[:i].String()]
+			if n < 1 || n > len(list) {
+				return nil
+			}
+			v = list[n-1]
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[part]
+	}
+	return v
+}
+
+// collectTOMLStrings sammelt die Zeichenketten eines Werts, deren Schlüsselpfad passt.
+// Inline-Tabellen in Arrays tauchen nicht in MetaData.Keys auf und werden hier durchlaufen.
+func collectTOMLStrings(v interface{}, key string, keys []string, values *[]string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(val))
+		for k := range val {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			collectTOMLStrings(val[k], joinKey(key, k), keys, values)
+		}
+	case []map[string]interface{}:
+		for _, child := range val {
+			collectTOMLStrings(child, key, keys, values)
+		}
+	case []interface{}:
+		for _, child := range val {
+			collectTOMLStrings(child, key, keys, values)
+		}
+	case string:
+		if val != "" && matchesKey(key, keys) {
+			*values = append(*values, val)
+		}
+	}
+}
+
+// findTOMLKey liefert den Offset hinter dem "=" der nächsten Zuweisung an name,
+// auch in Inline-Tabellen, mit Anführungszeichen oder als Teil eines Pfads (a.name).
+func findTOMLKey(block []byte, name string) int {
+	pattern := `(?m)(?:^|[\s{,.])(?:` + regexp.QuoteMeta(name) + `|"` + regexp.QuoteMeta(name) + `"|'` +
+		regexp.QuoteMeta(name) + `')[ \t]*=`
+	loc := regexp.MustCompile(pattern).FindIndex(block)
+	if loc == nil {
+		return -1
+	}
+	return loc[1]
+}
+
+// joinKey verbindet Schlüssel verschachtelter Felder mit einem Punkt.
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// matchesKey prüft einen Schlüsselpfad gegen Muster wie "image" oder "params.*_url".
+func matchesKey(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, key); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// runeOffset liefert den Byte-Offset des n-ten Zeichens.
+func runeOffset(b []byte, n int) int {
+	offset := 0
+	for i := 0; i < n && offset < len(b); i++ {
+		_, size := utf8.DecodeRune(b[offset:])
+		offset += size
+	}
+	return offset
+}
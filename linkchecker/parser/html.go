@@ -4,46 +4,162 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"regexp"
+	"strings"
 
 	"golang.org/x/net/html"
 )
 
+// linkAttribute beschreibt das URL-Attribut eines HTML-Elements.
+type linkAttribute struct {
+	name string
+	kind LinkKind
+}
+
+// linkAttributes ordnet HTML-Elementen ihr URL-Attribut und die Art des Links zu.
+var linkAttributes = map[string]linkAttribute{
+	"a":      {"href", KindLink},
+	"area":   {"href", KindLink},
+	"img":    {"src", KindImage},
+	"iframe": {"src", KindResource},
+	"link":   {"href", KindResource},
+	"script": {"src", KindResource},
+	"source": {"src", KindResource},
+}
+
+// attributePatterns finden den Anfang des Werts der URL-Attribute aus linkAttributes.
+var attributePatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, attr := range linkAttributes {
+		patterns[attr.name] = regexp.MustCompile(`(?i)[\s/]` + regexp.QuoteMeta(attr.name) + `\s*=\s*["']?`)
+	}
+	return patterns
+}()
+
 // ExtractLinksFromHTMLFile liest eine HTML-Datei ein und gibt alle gefundenen Links (href) zurück.
 func ExtractLinksFromHTMLFile(path string) ([]string, error) {
-	file, err := os.Open(path)
+	doc, err := ParseHTMLFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return hrefURLs(doc), nil
+}
+
+// ExtractLinksFromHTML extrahiert alle Links (href) aus HTML-Content.
+func ExtractLinksFromHTML(content []byte) []string {
+	return hrefURLs(ParseHTML(content))
+}
+
+// hrefURLs liefert nur die Ziele von <a href> und <area href>. Bilder, Skripte und
+// andere Ressourcen liefert ParseHTML.
+func hrefURLs(doc Document) []string {
+	var urls []string
+	for _, link := range doc.Links {
+		if link.Kind == KindLink {
+			urls = append(urls, link.URL)
+		}
+	}
+	return urls
+}
+
+// ParseHTMLFile liest eine HTML-Datei ein und liefert alle Links mit Position.
+func ParseHTMLFile(path string) (Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Document{}, err
+	}
 	defer file.Close()
 
 	content, err := io.ReadAll(file)
 	if err != nil {
-		return nil, err
+		return Document{}, err
 	}
 
-	return ExtractLinksFromHTML(content), nil
+	return ParseHTML(content), nil
 }
 
-// ExtractLinksFromHTML extrahiert alle Links (href) aus HTML-Content.
-func ExtractLinksFromHTML(content []byte) []string {
-	var links []string
-	doc, err := html.Parse(bytes.NewReader(content))
-	if err != nil {
-		return links
-	}
-	var f func(*html.Node)
-	f = func(n *html.Node) {
-		if n.Type == html.ElementNode && n.Data == "a" {
-			for _, attr := range n.Attr {
-				if attr.Key == "href" {
-					links = append(links, attr.Val)
-				}
+// ParseHTML parst HTML-Content und liefert alle Links mit Position sowie die Sprungziele.
+func ParseHTML(content []byte) Document {
+	links, anchors, directives := extractHTMLLinks(content, 0)
+	idx := newLineIndex(content)
+	idx.locate(links)
+	applySuppressions(links, directives, idx)
+	return Document{Links: links, Anchors: anchors}
+}
+
+// extractHTMLLinks sucht Links, Sprungziele und Steuerkommentare in einem HTML-Fragment.
+// offset ist die Position des Fragments im umgebenden Quelltext und wird auf alle
+// Positionen aufgeschlagen.
+func extractHTMLLinks(content []byte, offset int) ([]Link, []string, []directive) {
+	var links []Link
+	var anchors []string
+	var directives []directive
+	z := html.NewTokenizer(bytes.NewReader(content))
+	pos := 0
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		start := pos
+		pos += len(z.Raw())
+		if tt == html.CommentToken {
+			if d, ok := parseDirective(string(z.Text()), offset+start, offset+pos); ok {
+				directives = append(directives, d)
 			}
+			continue
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
 		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			f(c)
+
+		name, hasAttr := z.TagName()
+		target, isLink := linkAttributes[string(name)]
+
+		var value, rel string
+		found := false
+		for hasAttr {
+			var key, val []byte
+			key, val, hasAttr = z.TagAttr()
+			switch {
+			// Sprungziele sind IDs beliebiger Elemente und benannte Anker (<a name>)
+			case string(key) == "id" && len(val) > 0,
+				string(key) == "name" && string(name) == "a" && len(val) > 0:
+				anchors = append(anchors, string(val))
+			case isLink && string(key) == target.name:
+				value, found = string(val), true
+			case string(key) == "rel":
+				rel = strings.ToLower(string(val))
+			}
+		}
+		// Verbindungs-Hinweise zeigen auf Hosts, nicht auf abrufbare Dokumente
+		if !found || strings.Contains(rel, "preconnect") || strings.Contains(rel, "dns-prefetch") {
+			continue
+		}
+
+		link := Link{URL: value, Kind: target.kind, Start: offset + start}
+		if i := locateAttribute(content[start:pos], target.name, value); i >= 0 {
+			link.Start = offset + start + i
+			link.End = link.Start + len(value)
+		}
+		links = append(links, link)
+	}
+	return links, anchors, directives
+}
+
+// locateAttribute sucht den Wert eines Attributs in den Rohdaten eines Tags. Die Rohdaten
+// enthalten den Wert nur dann wörtlich, wenn er keine Entities enthält. Der Name muss
+// als ganzes Attribut vorkommen, damit etwa href nicht in data-href gefunden wird.
+func locateAttribute(raw []byte, name, value string) int {
+	if value == "" {
+		return -1
+	}
+	found := -1
+	// Wie beim Tokenizer gilt bei doppelten Attributen das letzte
+	for _, loc := range attributePatterns[name].FindAllIndex(raw, -1) {
+		if bytes.HasPrefix(raw[loc[1]:], []byte(value)) {
+			found = loc[1]
 		}
 	}
-	f(doc)
-	return links
+	return found
 }
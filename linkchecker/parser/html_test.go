@@ -1,12 +1,15 @@
 package parser
 
 import (
+	"bytes"
 	"os"
+	"reflect"
 	"testing"
 )
 
 func TestExtractLinksFromHTML(t *testing.T) {
-	htmlContent := []byte(`<html><body><a href="https://example.com">Example</a><a href='test.html'>Test</a></body></html>`)
+	htmlContent := []byte(`<html><head><script src="app.js"></script></head><body><a href="https://example.com">Example</a>` +
+		`<img src="logo.png"><a href='test.html'>Test</a></body></html>`)
 	links := ExtractLinksFromHTML(htmlContent)
 
 	want := []string{"https://example.com", "test.html"}
@@ -41,3 +44,78 @@ func TestExtractLinksFromHTMLFile(t *testing.T) {
 		t.Errorf("expected [https://golang.org], got %v", links)
 	}
 }
+
+func TestParseHTML(t *testing.T) {
+	htmlContent := []byte("<html>\n<head><link rel=\"stylesheet\" href=\"/style.css\"><link rel=\"preconnect\" href=\"https://cdn.example\"></head>\n" +
+		"<body><a title=\"docs\" href=\"docs/\">Docs</a>\n<img src=\"logo.png\"></body></html>")
+	doc := ParseHTML(htmlContent)
+
+	want := []Link{
+		{URL: "/style.css", Kind: KindResource, Line: 2, Column: 36},
+		{URL: "docs/", Kind: KindLink, Line: 3, Column: 29},
+		{URL: "logo.png", Kind: KindImage, Line: 4, Column: 11},
+	}
+	if len(doc.Links) != len(want) {
+		t.Fatalf("expected %d links, got %d: %+v", len(want), len(doc.Links), doc.Links)
+	}
+	for i, w := range want {
+		got := doc.Links[i]
+		if got.URL != w.URL || got.Kind != w.Kind || got.Line != w.Line || got.Column != w.Column {
+			t.Errorf("link %d: expected %+v, got %+v", i, w, got)
+		}
+		if string(htmlContent[got.Start:got.End]) != w.URL {
+			t.Errorf("link %d: offsets point to %q, expected %q", i, htmlContent[got.Start:got.End], w.URL)
+		}
+	}
+}
+
+func TestParseHTML_AttributeBoundary(t *testing.T) {
+	htmlContent := []byte(`<a data-href="https://example.com/old" xlink:href="https://example.com/old" HREF = 'https://example.com/old'>x</a>` +
+		`<img data-src="logo.png" src="logo.png">`)
+	doc := ParseHTML(htmlContent)
+
+	want := []struct {
+		url   string
+		start int
+	}{
+		{"https://example.com/old", bytes.Index(htmlContent, []byte("'https")) + 1},
+		{"logo.png", bytes.LastIndex(htmlContent, []byte(`"logo.png"`)) + 1},
+	}
+	if len(doc.Links) != len(want) {
+		t.Fatalf("expected %d links, got %+v", len(want), doc.Links)
+	}
+	for i, w := range want {
+		got := doc.Links[i]
+		if got.URL != w.url || got.Start != w.start || got.End != w.start+len(w.url) {
+			t.Errorf("link %d: expected %q at %d, got %+v", i, w.url, w.start, got)
+		}
+	}
+}
+
+func TestParseHTML_Suppressions(t *testing.T) {
+	htmlContent := []byte("<body>\n" +
+		"<!-- linkcheck-disable-next-line -->\n" +
+		"<a href=\"https://a.example\">A</a>\n" +
+		"<a href=\"https://b.example\">B</a>\n" +
+		"</body>")
+	doc := ParseHTML(htmlContent)
+
+	if len(doc.Links) != 2 {
+		t.Fatalf("expected 2 links, got %+v", doc.Links)
+	}
+	if !doc.Links[0].Suppressed {
+		t.Errorf("expected %s to be suppressed", doc.Links[0].URL)
+	}
+	if doc.Links[1].Suppressed {
+		t.Errorf("expected %s not to be suppressed", doc.Links[1].URL)
+	}
+}
+
+func TestParseHTML_Anchors(t *testing.T) {
+	doc := ParseHTML([]byte(`<h1 id="intro">Intro</h1><a name="old"></a><div name="ignored"><p id="">x</p></div>`))
+
+	want := []string{"intro", "old"}
+	if !reflect.DeepEqual(doc.Anchors, want) {
+		t.Errorf("expected anchors %v, got %v", want, doc.Anchors)
+	}
+}
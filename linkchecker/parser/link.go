@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// LinkKind beschreibt, aus welchem Konstrukt ein Link stammt.
+type LinkKind string
+
+const (
+	// KindLink ist ein normaler Link ([text](url) oder <a href>).
+	KindLink LinkKind = "link"
+	// KindImage ist ein Bild (![alt](url) oder <img src>).
+	KindImage LinkKind = "image"
+	// KindAutoLink ist ein Autolink (<https://...>).
+	KindAutoLink LinkKind = "autolink"
+	// KindDefinition ist eine Referenzdefinition ([label]: url).
+	KindDefinition LinkKind = "definition"
+	// KindResource ist eine eingebundene Ressource (<script>, <link>, <iframe>, ...).
+	KindResource LinkKind = "resource"
+)
+
+// Link ist ein gefundener Link mit seiner Position im Quelltext.
+type Link struct {
+	URL    string
+	Kind   LinkKind
+	Line   int
+	Column int
+	// Start und End sind die Byte-Offsets der URL im Quelltext.
+	// End ist 0, wenn die URL nicht wörtlich im Quelltext steht.
+	Start int
+	End   int
+	// Suppressed ist gesetzt, wenn ein linkcheck-disable-Kommentar den Link ausnimmt;
+	// SuppressReason enthält den optional angegebenen Grund.
+	Suppressed     bool
+	SuppressReason string
+}
+
+// Finding ist ein Lint-Hinweis, der beim Parsen gefunden wurde.
+type Finding struct {
+	Rule    string
+	Message string
+	Line    int
+	Column  int
+}
+
+// Document ist das Ergebnis des Parsens einer Datei.
+type Document struct {
+	Links    []Link
+	Findings []Finding
+	// Anchors sind die Sprungziele des Dokuments: die IDs der Überschriften sowie
+	// id- und name-Attribute in HTML.
+	Anchors []string
+}
+
+// URLs gibt die URLs aller Links in Dokumentreihenfolge zurück.
+func (d Document) URLs() []string {
+	urls := make([]string, 0, len(d.Links))
+	for _, link := range d.Links {
+		urls = append(urls, link.URL)
+	}
+	return urls
+}
+
+// sortLinks sortiert Links nach ihrer Position im Quelltext.
+func sortLinks(links []Link) {
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].Start < links[j].Start
+	})
+}
+
+// sortFindings sortiert Lint-Hinweise nach ihrer Position im Quelltext.
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+}
+
+// lineIndex bildet Byte-Offsets auf Zeilen und Spalten ab.
+type lineIndex struct {
+	source []byte
+	starts []int
+}
+
+func newLineIndex(source []byte) lineIndex {
+	starts := []int{0}
+	for i, c := range source {
+		if c == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return lineIndex{source: source, starts: starts}
+}
+
+// position liefert Zeile und Spalte (beide 1-basiert, Spalte in Zeichen) zu einem Offset.
+func (idx lineIndex) position(offset int) (int, int) {
+	if offset > len(idx.source) {
+		offset = len(idx.source)
+	}
+	line := sort.Search(len(idx.starts), func(i int) bool {
+		return idx.starts[i] > offset
+	}) - 1
+	column := utf8.RuneCount(idx.source[idx.starts[line]:offset]) + 1
+	return line + 1, column
+}
+
+// locate setzt Zeile und Spalte aller Links anhand ihres Start-Offsets.
+func (idx lineIndex) locate(links []Link) {
+	for i := range links {
+		links[i].Line, links[i].Column = idx.position(links[i].Start)
+	}
+}
+
+// offsetOf liefert die Position von part in source, falls part ein Teil-Slice von source ist.
+// Goldmark gibt Link-Ziele als Teil-Slices des Quelltexts zurück, wodurch sich ihre
+// exakte Position ohne erneutes Suchen bestimmen lässt.
+func offsetOf(source, part []byte) (int, bool) {
+	if len(part) == 0 || len(source) == 0 {
+		return 0, false
+	}
+	offset := cap(source) - cap(part)
+	if offset < 0 || offset+len(part) > len(source) || &source[offset] != &part[0] {
+		return 0, false
+	}
+	return offset, true
+}
@@ -1,44 +1,289 @@
 package parser
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strings"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	gmparser "github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
+// Regeln für Lint-Hinweise in Markdown-Dateien.
+const (
+	RuleUnusedReference    = "unused-reference"
+	RuleUndefinedReference = "undefined-reference"
+)
+
+// Dialect bestimmt, welche Markdown-Erweiterungen beim Parsen aktiv sind.
+type Dialect string
+
+const (
+	// DialectCommonMark parst reines CommonMark.
+	DialectCommonMark Dialect = "commonmark"
+	// DialectGFM parst GitHub Flavored Markdown mit Linkify, Tabellen,
+	// Durchstreichungen und Fußnoten.
+	DialectGFM Dialect = "gfm"
+)
+
+// Options bündelt die Einstellungen für das Parsen von Markdown.
+type Options struct {
+	// Dialect ist der Markdown-Dialekt; leer bedeutet CommonMark.
+	Dialect Dialect
+	// FrontMatterKeys sind Schlüssel oder Muster (path.Match) der Front-Matter-Felder,
+	// deren Werte als Links geprüft werden. Verschachtelte Felder werden mit Punkten
+	// verbunden, z.B. "params.image".
+	FrontMatterKeys []string
+}
+
+// fullReferencePattern findet Referenz-Links der Form [text][label] und [label][].
+var fullReferencePattern = regexp.MustCompile(`\[([^\[\]]+)\]\[([^\[\]]*)\]`)
+
 // ExtractLinksFromFile liest eine Markdown-Datei ein und gibt alle gefundenen Links zurück.
 func ExtractLinksFromFile(path string) ([]string, error) {
-	file, err := os.Open(path)
+	doc, err := ParseMarkdownFile(path, Options{})
 	if err != nil {
 		return nil, err
 	}
+	return doc.URLs(), nil
+}
+
+// ExtractLinks extrahiert alle Links aus Markdown-Content.
+func ExtractLinks(content []byte) []string {
+	return ParseMarkdown(content, Options{}).URLs()
+}
+
+// ParseMarkdownFile liest eine Markdown-Datei ein und liefert alle Links mit Position.
+func ParseMarkdownFile(path string, opts Options) (Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Document{}, err
+	}
 	defer file.Close()
 
 	content, err := io.ReadAll(file)
 	if err != nil {
-		return nil, err
+		return Document{}, err
 	}
 
-	return ExtractLinks(content), nil
+	return ParseMarkdown(content, opts), nil
 }
 
-// ExtractLinks extrahiert alle Links aus Markdown-Content.
-func ExtractLinks(content []byte) []string {
-	var links []string
-	md := goldmark.New()
-	reader := text.NewReader(content)
-	doc := md.Parser().Parse(reader)
+// ParseMarkdown parst Markdown-Content und liefert alle Links mit Position, die
+// Sprungziele sowie Lint-Hinweise zu unbenutzten und undefinierten Referenzen.
+func ParseMarkdown(content []byte, opts Options) Document {
+	var result Document
+	// Positionen beziehen sich immer auf den unveränderten Quelltext
+	idx := newLineIndex(content)
+
+	// Front Matter wird separat ausgewertet und für Goldmark ausgeblendet
+	if fm, ok := findFrontMatter(content); ok {
+		result.Links = extractFrontMatterLinks(content, fm, opts.FrontMatterKeys)
+		content = maskFrontMatter(content, fm)
+	}
+
+	var directives []directive
+	headings := headingAnchors{}
+	md := newMarkdown(opts.Dialect)
+	pc := gmparser.NewContext()
+	doc := md.Parser().Parse(text.NewReader(content), gmparser.WithContext(pc))
+
+	// Referenzdefinitionen sind Links an der Stelle ihrer Definition
+	definitions := map[int]gmparser.Reference{}
+	used := map[int]bool{}
+	for _, ref := range pc.References() {
+		start, ok := offsetOf(content, ref.Destination())
+		if !ok {
+			continue
+		}
+		definitions[start] = ref
+		result.Links = append(result.Links, Link{
+			URL:   string(ref.Destination()),
+			Kind:  KindDefinition,
+			Start: start,
+			End:   start + len(ref.Destination()),
+		})
+	}
 
 	//nolint:errcheck // ast.Walk error is not relevant for link extraction
 	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
-		if link, ok := n.(*ast.Link); ok && entering {
-			links = append(links, string(link.Destination))
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Link:
+			result.Links = appendDestination(result.Links, content, node.Destination, KindLink, n, definitions, used)
+		case *ast.Image:
+			result.Links = appendDestination(result.Links, content, node.Destination, KindImage, n, definitions, used)
+		case *ast.AutoLink:
+			label := node.Label(content)
+			url := string(node.URL(content))
+			if node.AutoLinkType == ast.AutoLinkEmail {
+				url = "mailto:" + url
+			}
+			// Die Position zeigt auf die Beschriftung; steht die URL dort nicht wörtlich
+			// (mailto: bei E-Mail-Adressen, http:// bei www.-Links), bleibt End 0
+			link := Link{URL: url, Kind: KindAutoLink, Start: blockStart(n)}
+			if start, ok := offsetOf(content, label); ok {
+				link.Start = start
+				if string(label) == url {
+					link.End = start + len(label)
+				}
+			}
+			result.Links = append(result.Links, link)
+		case *ast.RawHTML:
+			if node.Segments.Len() > 0 {
+				start := node.Segments.At(0).Start
+				stop := node.Segments.At(node.Segments.Len() - 1).Stop
+				links, anchors, found := extractHTMLLinks(content[start:stop], start)
+				result.Links = append(result.Links, links...)
+				result.Anchors = append(result.Anchors, anchors...)
+				directives = append(directives, found...)
+			}
+		case *ast.HTMLBlock:
+			if start, stop, ok := blockRange(node); ok {
+				if node.HasClosure() && node.ClosureLine.Stop > stop {
+					stop = node.ClosureLine.Stop
+				}
+				links, anchors, found := extractHTMLLinks(content[start:stop], start)
+				result.Links = append(result.Links, links...)
+				result.Anchors = append(result.Anchors, anchors...)
+				directives = append(directives, found...)
+			}
+		case *ast.Paragraph, *ast.Heading, *ast.TextBlock:
+			if heading, ok := n.(*ast.Heading); ok {
+				result.Anchors = append(result.Anchors, headings.anchor(headingText(heading, content)))
+			}
+			result.Findings = append(result.Findings, undefinedReferences(content, n, pc, idx, opts.Dialect)...)
 		}
 		return ast.WalkContinue, nil
 	})
 
-	return links
+	for start, ref := range definitions {
+		if !used[start] {
+			line, column := idx.position(start)
+			result.Findings = append(result.Findings, Finding{
+				Rule:    RuleUnusedReference,
+				Message: fmt.Sprintf("reference definition [%s] is never used", ref.Label()),
+				Line:    line,
+				Column:  column,
+			})
+		}
+	}
+
+	sortLinks(result.Links)
+	idx.locate(result.Links)
+	applySuppressions(result.Links, directives, idx)
+	sortFindings(result.Findings)
+	return result
+}
+
+// newMarkdown erzeugt einen Goldmark-Parser für den angegebenen Dialekt.
+func newMarkdown(dialect Dialect) goldmark.Markdown {
+	if dialect == DialectGFM {
+		return goldmark.New(goldmark.WithExtensions(
+			extension.Linkify,
+			extension.Table,
+			extension.Strikethrough,
+			extension.Footnote,
+		))
+	}
+	return goldmark.New()
+}
+
+// appendDestination fügt das Ziel eines Links oder Bildes hinzu. Ziele von Referenz-Links
+// zeigen auf den Quelltext ihrer Definition und werden dort bereits erfasst.
+func appendDestination(links []Link, source, destination []byte, kind LinkKind, n ast.Node,
+	definitions map[int]gmparser.Reference, used map[int]bool) []Link {
+	if len(destination) == 0 {
+		return links
+	}
+
+	start, ok := offsetOf(source, destination)
+	if !ok {
+		return append(links, Link{URL: string(destination), Kind: kind, Start: blockStart(n)})
+	}
+	if _, isReference := definitions[start]; isReference {
+		used[start] = true
+		return links
+	}
+	return append(links, Link{URL: string(destination), Kind: kind, Start: start, End: start + len(destination)})
+}
+
+// undefinedReferences meldet Referenz-Links der Form [text][label], deren Label nicht
+// definiert ist. Goldmark behandelt solche Links als Text, daher wird der Quelltext des
+// Blocks durchsucht; Code-Spans werden dabei ausgeblendet.
+func undefinedReferences(source []byte, block ast.Node, pc gmparser.Context, idx lineIndex, dialect Dialect) []Finding {
+	start, stop, ok := blockRange(block)
+	if !ok {
+		return nil
+	}
+
+	masked := make([]byte, stop-start)
+	copy(masked, source[start:stop])
+	//nolint:errcheck // ast.Walk error is not relevant for masking
+	ast.Walk(block, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if _, isCode := n.(*ast.CodeSpan); !isCode || !entering {
+			return ast.WalkContinue, nil
+		}
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if t, ok := c.(*ast.Text); ok {
+				for i := t.Segment.Start; i < t.Segment.Stop && i < stop; i++ {
+					masked[i-start] = ' '
+				}
+			}
+		}
+		return ast.WalkSkipChildren, nil
+	})
+
+	var findings []Finding
+	for _, match := range fullReferencePattern.FindAllSubmatchIndex(masked, -1) {
+		label := masked[match[4]:match[5]]
+		if util.IsBlank(label) {
+			label = masked[match[2]:match[3]]
+		}
+		// Fußnoten-Verweise wie [^1] sind in GFM keine Link-Referenzen
+		if dialect == DialectGFM && label[0] == '^' {
+			continue
+		}
+		if _, defined := pc.Reference(util.ToLinkReference(label)); defined {
+			continue
+		}
+		line, column := idx.position(start + match[0])
+		findings = append(findings, Finding{
+			Rule:    RuleUndefinedReference,
+			Message: fmt.Sprintf("reference [%s] is not defined", strings.TrimSpace(string(label))),
+			Line:    line,
+			Column:  column,
+		})
+	}
+	return findings
+}
+
+// blockRange liefert den Bereich eines Blocks im Quelltext.
+func blockRange(n ast.Node) (int, int, bool) {
+	lines := n.Lines()
+	if lines == nil || lines.Len() == 0 {
+		return 0, 0, false
+	}
+	return lines.At(0).Start, lines.At(lines.Len() - 1).Stop, true
+}
+
+// blockStart liefert den Anfang des Blocks, der einen Inline-Knoten enthält.
+func blockStart(n ast.Node) int {
+	for ; n != nil; n = n.Parent() {
+		if n.Type() == ast.TypeBlock {
+			if start, _, ok := blockRange(n); ok {
+				return start
+			}
+		}
+	}
+	return 0
 }
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -41,3 +42,272 @@ func TestExtractLinksFromFile(t *testing.T) {
 		t.Errorf("expected [https://golang.org], got %v", links)
 	}
 }
+
+func TestParseMarkdown(t *testing.T) {
+	md := []byte("# Title\n" +
+		"\n" +
+		"A [link](https://a.example) and ![logo](img/logo.png \"Logo\").\n" +
+		"See <https://auto.example> and [ref][r1].\n" +
+		"\n" +
+		"<a href=\"https://html.example\">raw</a>\n" +
+		"\n" +
+		"[r1]: https://r1.example\n")
+	doc := ParseMarkdown(md, Options{})
+
+	want := []Link{
+		{URL: "https://a.example", Kind: KindLink, Line: 3, Column: 10},
+		{URL: "img/logo.png", Kind: KindImage, Line: 3, Column: 41},
+		{URL: "https://auto.example", Kind: KindAutoLink, Line: 4, Column: 6},
+		{URL: "https://html.example", Kind: KindLink, Line: 6, Column: 10},
+		{URL: "https://r1.example", Kind: KindDefinition, Line: 8, Column: 7},
+	}
+	if len(doc.Links) != len(want) {
+		t.Fatalf("expected %d links, got %d: %+v", len(want), len(doc.Links), doc.Links)
+	}
+	for i, w := range want {
+		got := doc.Links[i]
+		if got.URL != w.URL || got.Kind != w.Kind || got.Line != w.Line || got.Column != w.Column {
+			t.Errorf("link %d: expected %+v, got %+v", i, w, got)
+		}
+		if string(md[got.Start:got.End]) != w.URL {
+			t.Errorf("link %d: offsets point to %q, expected %q", i, md[got.Start:got.End], w.URL)
+		}
+	}
+	if len(doc.Findings) != 0 {
+		t.Errorf("expected no findings, got %+v", doc.Findings)
+	}
+}
+
+func TestParseMarkdown_AutoLinkOffsets(t *testing.T) {
+	md := []byte("Mail <user@example.com> or <https://auto.example>.\n")
+	doc := ParseMarkdown(md, Options{})
+
+	if len(doc.Links) != 2 {
+		t.Fatalf("expected 2 links, got %+v", doc.Links)
+	}
+	// The source only holds the address, not the mailto: URL
+	if mail := doc.Links[0]; mail.URL != "mailto:user@example.com" || mail.Start != 6 || mail.End != 0 || mail.Column != 7 {
+		t.Errorf("unexpected email autolink: %+v", mail)
+	}
+	if auto := doc.Links[1]; string(md[auto.Start:auto.End]) != "https://auto.example" {
+		t.Errorf("unexpected autolink offsets: %+v", auto)
+	}
+}
+
+func TestParseMarkdown_ReferenceFindings(t *testing.T) {
+	md := []byte("Uses [missing][nope] but not `a[i][j]`.\n" +
+		"\n" +
+		"[unused]: https://unused.example\n")
+	doc := ParseMarkdown(md, Options{})
+
+	if len(doc.Findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", doc.Findings)
+	}
+	if f := doc.Findings[0]; f.Rule != RuleUndefinedReference || f.Line != 1 || f.Column != 6 {
+		t.Errorf("unexpected finding for undefined reference: %+v", f)
+	}
+	if f := doc.Findings[1]; f.Rule != RuleUnusedReference || f.Line != 3 {
+		t.Errorf("unexpected finding for unused reference: %+v", f)
+	}
+}
+
+func TestParseMarkdown_GFM(t *testing.T) {
+	md := []byte("Visit https://bare.example today.\n" +
+		"\n" +
+		"| Name | Link |\n" +
+		"|------|------|\n" +
+		"| Go   | [go](https://go.dev) |\n" +
+		"\n" +
+		"Text with a note[^1][^2].\n" +
+		"\n" +
+		"[^1]: See [spec](https://spec.example).\n" +
+		"[^2]: Second note.\n")
+
+	commonMark := ParseMarkdown(md, Options{Dialect: DialectCommonMark})
+	for _, link := range commonMark.Links {
+		if link.URL == "https://bare.example" {
+			t.Errorf("CommonMark must not linkify bare URLs")
+		}
+	}
+
+	doc := ParseMarkdown(md, Options{Dialect: DialectGFM})
+	want := []string{"https://bare.example", "https://go.dev", "https://spec.example"}
+	urls := doc.URLs()
+	if len(urls) != len(want) {
+		t.Fatalf("expected %v, got %v", want, urls)
+	}
+	for i, link := range want {
+		if urls[i] != link {
+			t.Errorf("expected link %q, got %q", link, urls[i])
+		}
+	}
+	if doc.Links[0].Kind != KindAutoLink || doc.Links[0].Line != 1 || doc.Links[0].Column != 7 {
+		t.Errorf("unexpected bare URL link: %+v", doc.Links[0])
+	}
+	if len(doc.Findings) != 0 {
+		t.Errorf("expected no findings for footnotes, got %+v", doc.Findings)
+	}
+}
+
+func TestParseMarkdown_GFMLinkifyOffsets(t *testing.T) {
+	md := []byte("See www.example.com/old and https://bare.example.\n")
+	doc := ParseMarkdown(md, Options{Dialect: DialectGFM})
+
+	if len(doc.Links) != 2 {
+		t.Fatalf("expected 2 links, got %+v", doc.Links)
+	}
+	// The parser adds http:// that is not in the source, so there is no end offset
+	if www := doc.Links[0]; www.URL != "http://www.example.com/old" || www.Start != 4 || www.End != 0 || www.Column != 5 {
+		t.Errorf("unexpected www link: %+v", www)
+	}
+	if bare := doc.Links[1]; string(md[bare.Start:bare.End]) != "https://bare.example" {
+		t.Errorf("unexpected bare URL offsets: %+v", bare)
+	}
+}
+
+func TestParseMarkdown_FrontMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+	}{
+		{
+			name: "yaml",
+			md: "---\n" +
+				"title: Über uns\n" +
+				"canonical: \"https://canonical.example/about\"\n" +
+				"params:\n" +
+				"  source_url: https://source.example\n" +
+				"aliases:\n" +
+				"  - /old-about/\n" +
+				"---\n" +
+				"Body [link](https://body.example).\n",
+		},
+		{
+			name: "toml",
+			md: "+++\n" +
+				"title = \"Über uns\"\n" +
+				"canonical = \"https://canonical.example/about\"\n" +
+				"aliases = [\"/old-about/\"]\n" +
+				"[params]\n" +
+				"source_url = \"https://source.example\"\n" +
+				"+++\n" +
+				"Body [link](https://body.example).\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := []byte(tt.md)
+			doc := ParseMarkdown(md, Options{FrontMatterKeys: []string{"canonical", "aliases", "params.*_url"}})
+
+			want := map[string]bool{
+				"https://canonical.example/about": true,
+				"https://source.example":          true,
+				"/old-about/":                     true,
+				"https://body.example":            true,
+			}
+			if len(doc.Links) != len(want) {
+				t.Fatalf("expected %d links, got %+v", len(want), doc.Links)
+			}
+			for _, link := range doc.Links {
+				if !want[link.URL] {
+					t.Errorf("unexpected link %q", link.URL)
+				}
+				if string(md[link.Start:link.End]) != link.URL {
+					t.Errorf("offsets of %q point to %q", link.URL, md[link.Start:link.End])
+				}
+				if link.URL == "https://canonical.example/about" && (link.Line != 3 || link.Kind != KindFrontMatter) {
+					t.Errorf("unexpected canonical link: %+v", link)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMarkdown_TOMLFrontMatterPositions(t *testing.T) {
+	md := []byte("+++\n" +
+		"title = \"https://example.com/docs\"\n" +
+		"canonical = \"https://example.com\"\n" +
+		"aliases = [\"/a/\", \"/a/\"]\n" +
+		"[params]\n" +
+		"image = \"https://example.com\"\n" +
+		"source_url = \"https://example.com/docs\"\n" +
+		"[[links]]\n" +
+		"url = \"/a/\"\n" +
+		"[[links]]\n" +
+		"url = \"https://example.com\"\n" +
+		"+++\n")
+	doc := ParseMarkdown(md, Options{FrontMatterKeys: []string{"canonical", "aliases", "params.*", "links.url"}})
+
+	want := []struct {
+		url  string
+		line int
+	}{
+		{"https://example.com", 3},
+		{"/a/", 4},
+		{"/a/", 4},
+		{"https://example.com", 6},
+		{"https://example.com/docs", 7},
+		{"/a/", 9},
+		{"https://example.com", 11},
+	}
+	if len(doc.Links) != len(want) {
+		t.Fatalf("expected %d links, got %+v", len(want), doc.Links)
+	}
+	for i, w := range want {
+		link := doc.Links[i]
+		if link.URL != w.url || link.Line != w.line || string(md[link.Start:link.End]) != w.url {
+			t.Errorf("link %d: got %q on line %d, want %q on line %d", i, link.URL, link.Line, w.url, w.line)
+		}
+	}
+	if doc.Links[1].Start == doc.Links[2].Start {
+		t.Errorf("repeated values must point to different positions: %+v", doc.Links[1:3])
+	}
+}
+
+func TestParseMarkdown_Suppressions(t *testing.T) {
+	md := []byte("<!-- linkcheck-disable-next-line: flaky upstream -->\n" +
+		"[a](https://a.example)\n" +
+		"[b](https://b.example)\n" +
+		"\n" +
+		"<!-- linkcheck-disable vendor docs -->\n" +
+		"[c](https://c.example)\n" +
+		"\n" +
+		"<!-- linkcheck-enable -->\n" +
+		"[d](https://d.example)\n")
+	doc := ParseMarkdown(md, Options{})
+
+	want := map[string]string{
+		"https://a.example": "flaky upstream",
+		"https://c.example": "vendor docs",
+	}
+	if len(doc.Links) != 4 {
+		t.Fatalf("expected 4 links, got %+v", doc.Links)
+	}
+	for _, link := range doc.Links {
+		reason, suppressed := want[link.URL]
+		if link.Suppressed != suppressed || link.SuppressReason != reason {
+			t.Errorf("%s: expected suppressed=%v reason=%q, got suppressed=%v reason=%q",
+				link.URL, suppressed, reason, link.Suppressed, link.SuppressReason)
+		}
+	}
+}
+
+func TestParseMarkdown_Anchors(t *testing.T) {
+	md := []byte("# Getting Started\n" +
+		"\n" +
+		"## Install `linkchecker` (v2.0)!\n" +
+		"\n" +
+		"## Getting Started\n" +
+		"\n" +
+		"Setext *heading*\n" +
+		"---\n" +
+		"\n" +
+		"<a name=\"legacy\"></a><span id=\"custom-id\">x</span>\n")
+	doc := ParseMarkdown(md, Options{})
+
+	want := []string{"getting-started", "install-linkchecker-v20", "getting-started-1", "setext-heading", "legacy", "custom-id"}
+	if !reflect.DeepEqual(doc.Anchors, want) {
+		t.Errorf("expected anchors %v, got %v", want, doc.Anchors)
+	}
+}
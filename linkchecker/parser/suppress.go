@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Kommentare, mit denen Links von der Prüfung ausgenommen werden.
+const (
+	directiveDisable         = "linkcheck-disable"
+	directiveEnable          = "linkcheck-enable"
+	directiveDisableNextLine = "linkcheck-disable-next-line"
+)
+
+// directive ist ein Steuerkommentar wie <!-- linkcheck-disable-next-line: Grund -->.
+type directive struct {
+	name   string
+	reason string
+	// start und end begrenzen den Kommentar im Quelltext
+	start, end int
+}
+
+// parseDirective erkennt einen Steuerkommentar am Inhalt eines HTML-Kommentars.
+// Nach dem Namen kann, optional durch einen Doppelpunkt getrennt, ein Grund folgen.
+func parseDirective(comment string, start, end int) (directive, bool) {
+	comment = strings.TrimSpace(comment)
+	name, reason, _ := strings.Cut(comment, " ")
+	name, colonReason, hasColon := strings.Cut(name, ":")
+	if hasColon {
+		reason = colonReason + " " + reason
+	}
+
+	switch name {
+	case directiveDisable, directiveEnable, directiveDisableNextLine:
+	default:
+		return directive{}, false
+	}
+
+	reason = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(reason), ":"))
+	return directive{name: name, reason: reason, start: start, end: end}, true
+}
+
+// applySuppressions markiert Links, die durch Steuerkommentare ausgenommen sind.
+// Die Links müssen bereits ihre Zeilen kennen.
+func applySuppressions(links []Link, directives []directive, idx lineIndex) {
+	if len(directives) == 0 {
+		return
+	}
+
+	for i := range links {
+		for _, d := range directives {
+			switch d.name {
+			case directiveDisableNextLine:
+				line, _ := idx.position(d.end)
+				if links[i].Line == line+1 {
+					links[i].Suppressed, links[i].SuppressReason = true, d.reason
+				}
+			case directiveDisable:
+				if links[i].Start >= d.end && links[i].Start < enabledAt(directives, d.end, idx) {
+					links[i].Suppressed, links[i].SuppressReason = true, d.reason
+				}
+			}
+		}
+	}
+}
+
+// enabledAt liefert die Position des nächsten linkcheck-enable nach from oder das Dateiende.
+func enabledAt(directives []directive, from int, idx lineIndex) int {
+	for _, d := range directives {
+		if d.name == directiveEnable && d.start >= from {
+			return d.start
+		}
+	}
+	return len(idx.source) + 1
+}
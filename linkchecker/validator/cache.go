@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache speichert die Ergebnisse von HTTP-Links für eine begrenzte Zeit, damit
+// wiederholte Prüfungen (z.B. im Watch-Modus) keine erneuten Anfragen senden.
+// Dateilinks werden nie zwischengespeichert. Der Cache ist nebenläufig nutzbar.
+type Cache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// now liefert die aktuelle Zeit; in Tests ersetzbar
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	status  LinkStatus
+	expires time.Time
+}
+
+// NewCache erzeugt einen Cache, dessen Einträge nach ttl verfallen.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: make(map[string]cacheEntry), now: time.Now}
+}
+
+// get liefert ein noch gültiges Ergebnis für einen Link.
+func (c *Cache) get(link string) (LinkStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[link]
+	if !ok {
+		return LinkStatus{}, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, link)
+		return LinkStatus{}, false
+	}
+	return entry.status, true
+}
+
+// put speichert das Ergebnis eines Links.
+func (c *Cache) put(status LinkStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[status.Link] = cacheEntry{status: status, expires: c.now().Add(c.ttl)}
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+)
+
+// ErrorKind ordnet einen fehlgeschlagenen Link einer Fehlerkategorie zu.
+type ErrorKind string
+
+const (
+	// ErrorNotFound: der Server meldet 404 oder 410.
+	ErrorNotFound ErrorKind = "not-found"
+	// ErrorHTTPClient: sonstige 4xx-Antworten.
+	ErrorHTTPClient ErrorKind = "http-client-error"
+	// ErrorHTTPServer: 5xx-Antworten.
+	ErrorHTTPServer ErrorKind = "http-server-error"
+	// ErrorTimeout: die Anfrage hat das Timeout überschritten.
+	ErrorTimeout ErrorKind = "timeout"
+	// ErrorDNS: der Hostname konnte nicht aufgelöst werden.
+	ErrorDNS ErrorKind = "dns"
+	// ErrorTLS: TLS-Handshake oder Zertifikatsprüfung sind fehlgeschlagen.
+	ErrorTLS ErrorKind = "tls"
+	// ErrorConnection: die Verbindung wurde abgelehnt oder abgebrochen.
+	ErrorConnection ErrorKind = "connection"
+	// ErrorRequest: sonstige Fehler bei der HTTP-Anfrage.
+	ErrorRequest ErrorKind = "request"
+	// ErrorFileNotFound: die verlinkte Datei existiert nicht.
+	ErrorFileNotFound ErrorKind = "file-not-found"
+	// ErrorMissingIndex: das verlinkte Verzeichnis hat keine Index-Datei.
+	ErrorMissingIndex ErrorKind = "missing-index"
+	// ErrorAnchorNotFound: die verlinkte Datei hat kein Sprungziel für den Anker.
+	ErrorAnchorNotFound ErrorKind = "anchor-not-found"
+	// ErrorFile: sonstige Fehler beim Zugriff auf die Datei.
+	ErrorFile ErrorKind = "file-error"
+)
+
+// ErrorKinds sind alle Fehlerkategorien in stabiler Reihenfolge.
+var ErrorKinds = []ErrorKind{
+	ErrorNotFound,
+	ErrorHTTPClient,
+	ErrorHTTPServer,
+	ErrorTimeout,
+	ErrorDNS,
+	ErrorTLS,
+	ErrorConnection,
+	ErrorRequest,
+	ErrorFileNotFound,
+	ErrorMissingIndex,
+	ErrorAnchorNotFound,
+	ErrorFile,
+}
+
+// classifyStatus ordnet einen HTTP-Statuscode außerhalb von 2xx/3xx einer Kategorie zu.
+func classifyStatus(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		return ErrorNotFound
+	case statusCode >= 500:
+		return ErrorHTTPServer
+	default:
+		return ErrorHTTPClient
+	}
+}
+
+// classifyRequestError ordnet einen Fehler des HTTP-Clients einer Kategorie zu.
+func classifyRequestError(err error) ErrorKind {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var opErr *net.OpError
+
+	switch {
+	case os.IsTimeout(err) || errors.Is(err, os.ErrDeadlineExceeded):
+		return ErrorTimeout
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return ErrorTLS
+	case errors.As(err, &opErr):
+		return ErrorConnection
+	default:
+		return ErrorRequest
+	}
+}
+
+// classifyFileError ordnet einen Fehler beim Dateizugriff einer Kategorie zu.
+func classifyFileError(err error) ErrorKind {
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrorFileNotFound
+	}
+	return ErrorFile
+}
@@ -0,0 +1,213 @@
+package validator
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Inspection beschreibt die Prüfung eines HTTP-Links im Detail.
+type Inspection struct {
+	// Status ist das Ergebnis, wie es ValidateLinks liefern würde
+	Status LinkStatus
+	// Rule erklärt, warum der Link als gültig oder ungültig gilt
+	Rule string
+	// Attempts sind die Versuche der Prüfung (HEAD und ggf. GET)
+	Attempts []Attempt
+}
+
+// Attempt ist ein Versuch mit einer Methode, einschließlich aller Weiterleitungen.
+type Attempt struct {
+	Number int
+	Method string
+	// Comparison ist gesetzt, wenn der Versuch nur zum Vergleich ausgeführt wurde
+	// und das Ergebnis nicht beeinflusst
+	Comparison bool
+	Hops       []Hop
+	Error      string
+	Duration   time.Duration
+}
+
+// Hop ist eine einzelne HTTP-Anfrage eines Versuchs.
+type Hop struct {
+	URL        string
+	StatusCode int
+	Status     string
+	Header     http.Header
+	// Addresses sind die per DNS aufgelösten Adressen; leer bei wiederverwendeter Verbindung
+	Addresses  []string
+	RemoteAddr string
+	// ReusedConn ist gesetzt, wenn eine bestehende Verbindung verwendet wurde
+	ReusedConn bool
+	TLS        *TLSInfo
+	Timings    Timings
+	Error      string
+}
+
+// Timings sind die Dauern der Phasen einer Anfrage.
+type Timings struct {
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration
+	Total     time.Duration
+}
+
+// TLSInfo beschreibt die TLS-Verbindung einer Anfrage.
+type TLSInfo struct {
+	Version      string
+	CipherSuite  string
+	ServerName   string
+	Certificates []Certificate
+}
+
+// Certificate ist ein Zertifikat der vom Server gesendeten Kette.
+type Certificate struct {
+	Subject   string
+	Issuer    string
+	DNSNames  []string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// Inspect prüft einen HTTP-Link mit derselben Logik wie ValidateLinks und zeichnet
+// dabei jede Anfrage auf. War schon HEAD erfolgreich, wird zum Vergleich zusätzlich
+// GET ausgeführt.
+func Inspect(link string, opts Options) Inspection {
+	insp := &inspector{}
+	opts.inspector = insp
+
+	start := time.Now()
+	status := checkHTTP(link, opts)
+	status.Duration = time.Since(start)
+
+	if len(insp.attempts) == 1 {
+		c := newHTTPChecker(link, opts)
+		if resp, err := c.request(2, http.MethodGet); err == nil {
+			resp.Body.Close()
+		}
+		insp.attempts[1].Comparison = true
+	}
+
+	return Inspection{Status: status, Rule: describeRule(status), Attempts: insp.attempts}
+}
+
+// describeRule erklärt das Ergebnis einer HTTP-Prüfung.
+func describeRule(status LinkStatus) string {
+	switch {
+	case status.StatusCode == 0:
+		return fmt.Sprintf("request failed (%s): links that cannot be requested are invalid", status.ErrorKind)
+	case status.Valid:
+		return fmt.Sprintf("status %d is 2xx or 3xx: valid", status.StatusCode)
+	default:
+		return fmt.Sprintf("status %d is not 2xx or 3xx: invalid (%s)", status.StatusCode, status.ErrorKind)
+	}
+}
+
+// inspector sammelt die Versuche und Anfragen einer Prüfung.
+type inspector struct {
+	mu       sync.Mutex
+	attempts []Attempt
+}
+
+// begin startet einen Versuch und liefert einen Transport, der dessen Anfragen aufzeichnet.
+// Jeder Versuch nutzt neue Verbindungen, damit DNS, Verbindungsaufbau und TLS sichtbar sind.
+func (in *inspector) begin(number int, method string) http.RoundTripper {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.attempts = append(in.attempts, Attempt{Number: number, Method: method})
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	return &tracingTransport{base: transport, inspector: in}
+}
+
+// end schließt den laufenden Versuch ab.
+func (in *inspector) end(err error, duration time.Duration) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	attempt := &in.attempts[len(in.attempts)-1]
+	attempt.Duration = duration
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+}
+
+// addHop hängt eine Anfrage an den laufenden Versuch an.
+func (in *inspector) addHop(hop Hop) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	attempt := &in.attempts[len(in.attempts)-1]
+	attempt.Hops = append(attempt.Hops, hop)
+}
+
+// tracingTransport zeichnet jede Anfrage mit httptrace auf.
+type tracingTransport struct {
+	base      http.RoundTripper
+	inspector *inspector
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	hop := Hop{URL: req.URL.String()}
+	var start, dnsStart, connectStart, tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			hop.Timings.DNS = time.Since(dnsStart)
+			for _, addr := range info.Addrs {
+				hop.Addresses = append(hop.Addresses, addr.String())
+			}
+		},
+		ConnectStart: func(network, addr string) { connectStart = time.Now() },
+		ConnectDone: func(network, addr string, err error) {
+			hop.Timings.Connect = time.Since(connectStart)
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			hop.Timings.TLS = time.Since(tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			hop.ReusedConn = info.Reused
+			if info.Conn != nil {
+				hop.RemoteAddr = info.Conn.RemoteAddr().String()
+			}
+		},
+		GotFirstResponseByte: func() { hop.Timings.FirstByte = time.Since(start) },
+	}
+
+	start = time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	hop.Timings.Total = time.Since(start)
+	if err != nil {
+		hop.Error = err.Error()
+	} else {
+		hop.StatusCode = resp.StatusCode
+		hop.Status = resp.Status
+		hop.Header = resp.Header.Clone()
+		if resp.TLS != nil {
+			hop.TLS = tlsInfo(resp.TLS)
+		}
+	}
+	t.inspector.addHop(hop)
+	return resp, err
+}
+
+// tlsInfo fasst den Zustand einer TLS-Verbindung zusammen.
+func tlsInfo(state *tls.ConnectionState) *TLSInfo {
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	for _, cert := range state.PeerCertificates {
+		info.Certificates = append(info.Certificates, Certificate{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+		})
+	}
+	return info
+}
@@ -1,7 +1,12 @@
 package validator
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +19,51 @@ type LinkStatus struct {
 	Valid      bool
 	Reason     string
 	StatusCode int
+	// ErrorKind ist die Fehlerkategorie ungültiger Links
+	ErrorKind ErrorKind
+	// Duration ist die Dauer der Prüfung
+	Duration time.Duration
+	// RedirectURL ist das Ziel nach allen Weiterleitungen; leer ohne Weiterleitung
+	RedirectURL string
+	// RedirectCode ist der Statuscode der ersten Weiterleitung (z.B. 301)
+	RedirectCode int
+	// PermanentRedirect ist gesetzt, wenn alle Weiterleitungen permanent sind (301/308)
+	PermanentRedirect bool
+}
+
+// Options bündelt die Einstellungen für die Link-Validierung.
+type Options struct {
+	// BasePath ist das Verzeichnis, gegen das relative Dateilinks aufgelöst werden.
+	BasePath string
+	// SiteRoot ist das Verzeichnis, gegen das root-relative Links (/pfad) aufgelöst werden.
+	// Ist es leer, werden solche Links als absolute Dateipfade behandelt.
+	SiteRoot string
+	// IndexFiles werden bei Links auf Verzeichnisse gesucht (z.B. index.html).
+	// Ist die Liste leer, gilt jedes existierende Verzeichnis als gültiges Ziel.
+	IndexFiles []string
+	Timeout    time.Duration
+	Workers    int
+	// Logger erhält Diagnosen zu jeder Anfrage (url, host, attempt, duration, status).
+	// Ohne Logger wird nichts protokolliert.
+	Logger *slog.Logger
+	// OnStart wird aufgerufen, bevor ein Link geprüft wird. Die Aufrufe erfolgen
+	// gleichzeitig aus mehreren Workern.
+	OnStart func(link string)
+	// OnResult wird für jedes Ergebnis aufgerufen, sobald es vorliegt.
+	// Die Aufrufe erfolgen nacheinander, nie gleichzeitig.
+	OnResult func(LinkStatus)
+	// Cache liefert bereits geprüfte HTTP-Links ohne erneute Anfrage; nil prüft immer.
+	Cache *Cache
+	// Document ist die Datei, die die Links enthält. Reine Anker (#abschnitt) werden
+	// gegen ihre Sprungziele geprüft.
+	Document string
+	// Anchors liefert die Sprungziele einer Datei. ok ist false, wenn die Datei keine
+	// Sprungziele kennt (z.B. Bilder); ihre Anker gelten dann als gültig. Die Aufrufe
+	// erfolgen gleichzeitig aus mehreren Workern. Ohne Anchors werden Anker nicht geprüft.
+	Anchors func(path string) (anchors []string, ok bool)
+
+	// inspector zeichnet die Anfragen für Inspect auf
+	inspector *inspector
 }
 
 // ValidateLinks prüft, ob Links erreichbar sind (HTTP) oder existieren (Dateipfad).
@@ -28,6 +78,15 @@ func ValidateLinksWithTimeout(links []string, basePath string, timeout time.Dura
 
 // ValidateLinksAsync prüft Links asynchron mit konfigurierbarer Anzahl von Workern.
 func ValidateLinksAsync(links []string, basePath string, timeout time.Duration, maxWorkers int) []LinkStatus {
+	return ValidateLinksWithOptions(links, Options{
+		BasePath: basePath,
+		Timeout:  timeout,
+		Workers:  maxWorkers,
+	})
+}
+
+// ValidateLinksWithOptions prüft Links asynchron mit den angegebenen Optionen.
+func ValidateLinksWithOptions(links []string, opts Options) []LinkStatus {
 	if len(links) == 0 {
 		return []LinkStatus{}
 	}
@@ -38,9 +97,9 @@ func ValidateLinksAsync(links []string, basePath string, timeout time.Duration,
 
 	// Worker-Pool starten
 	var wg sync.WaitGroup
-	for i := 0; i < maxWorkers; i++ {
+	for i := 0; i < opts.Workers; i++ {
 		wg.Add(1)
-		go worker(linkChan, resultChan, basePath, timeout, &wg)
+		go worker(linkChan, resultChan, opts, &wg)
 	}
 
 	// Links in den Channel senden
@@ -60,86 +119,284 @@ func ValidateLinksAsync(links []string, basePath string, timeout time.Duration,
 	// Ergebnisse sammeln
 	results := make([]LinkStatus, 0, len(links))
 	for result := range resultChan {
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
 		results = append(results, result)
 	}
 
 	return results
 }
 
-func worker(linkChan <-chan string, resultChan chan<- LinkStatus, basePath string, timeout time.Duration, wg *sync.WaitGroup) {
+func worker(linkChan <-chan string, resultChan chan<- LinkStatus, opts Options, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for link := range linkChan {
+		if opts.OnStart != nil {
+			opts.OnStart(link)
+		}
 		var status LinkStatus
+		start := time.Now()
 
-		if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
-			ok, reason, statusCode := checkHTTP(link, timeout)
-			status = LinkStatus{
-				Link:       link,
-				Valid:      ok,
-				Reason:     reason,
-				StatusCode: statusCode,
+		if isHTTPLink(link) {
+			if cached, ok := opts.cached(link); ok {
+				opts.logger().Debug("link cached", "url", link, "host", linkHost(link), "valid", cached.Valid)
+				resultChan <- cached
+				continue
 			}
-		} else {
-			ok, reason := checkFile(basePath, link)
-			status = LinkStatus{
-				Link:       link,
-				Valid:      ok,
-				Reason:     reason,
-				StatusCode: 0,
+			status = checkHTTP(link, opts)
+			status.Duration = time.Since(start)
+			if opts.Cache != nil {
+				opts.Cache.put(status)
 			}
+		} else {
+			status = checkFile(link, opts)
+			status.Duration = time.Since(start)
 		}
 
+		level := slog.LevelDebug
+		if !status.Valid {
+			level = slog.LevelInfo
+		}
+		opts.logger().Log(context.Background(), level, "link checked",
+			"url", link,
+			"host", linkHost(link),
+			"valid", status.Valid,
+			"status", status.StatusCode,
+			"error_kind", string(status.ErrorKind),
+			"duration", status.Duration,
+		)
+
 		resultChan <- status
 	}
 }
 
-func checkHTTP(url string, timeout time.Duration) (bool, string, int) {
-	client := &http.Client{
-		Timeout: timeout,
+// discardLogger verwirft alle Einträge.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// logger liefert den konfigurierten Logger oder einen, der nichts ausgibt.
+func (opts Options) logger() *slog.Logger {
+	if opts.Logger != nil {
+		return opts.Logger
+	}
+	return discardLogger
+}
+
+// cached liefert ein zwischengespeichertes Ergebnis, falls ein Cache gesetzt ist.
+func (opts Options) cached(link string) (LinkStatus, bool) {
+	if opts.Cache == nil {
+		return LinkStatus{}, false
+	}
+	return opts.Cache.get(link)
+}
+
+// linkHost liefert den Host eines Links; leer für Dateilinks.
+func linkHost(link string) string {
+	if u, err := url.Parse(link); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+// isHTTPLink meldet, ob ein Link per HTTP abgerufen wird. Dazu gehören auch
+// protokoll-relative Links (//host/pfad).
+func isHTTPLink(link string) bool {
+	return strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "//")
+}
+
+// requestURL liefert die URL, unter der ein Link abgerufen wird. Protokoll-relative
+// Links werden wie auf einer HTTPS-Seite mit https: ergänzt.
+func requestURL(link string) string {
+	if strings.HasPrefix(link, "//") {
+		return "https:" + link
+	}
+	return link
+}
+
+// httpChecker führt die Anfragen einer Link-Prüfung aus und merkt sich die Weiterleitungen.
+type httpChecker struct {
+	url    string
+	opts   Options
+	client *http.Client
+	// redirects sind die Statuscodes der Weiterleitungen in der Reihenfolge ihres Auftretens
+	redirects []int
+}
+
+func newHTTPChecker(url string, opts Options) *httpChecker {
+	c := &httpChecker{url: requestURL(url), opts: opts}
+	c.client = &http.Client{
+		Timeout: opts.Timeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Allow up to 10 redirects
 			if len(via) >= 10 {
 				return http.ErrUseLastResponse
 			}
+			if req.Response != nil {
+				c.redirects = append(c.redirects, req.Response.StatusCode)
+			}
 			return nil
 		},
 	}
+	return c
+}
+
+// request führt einen Versuch aus und protokolliert ihn
+func (c *httpChecker) request(attempt int, method string) (*http.Response, error) {
+	c.redirects = nil
+	if c.opts.inspector != nil {
+		c.client.Transport = c.opts.inspector.begin(attempt, method)
+	}
+	start := time.Now()
+	var resp *http.Response
+	var err error
+	if method == http.MethodHead {
+		resp, err = c.client.Head(c.url)
+	} else {
+		resp, err = c.client.Get(c.url)
+	}
+	duration := time.Since(start)
+	if c.opts.inspector != nil {
+		c.opts.inspector.end(err, duration)
+	}
+
+	attrs := []any{
+		"url", c.url,
+		"host", linkHost(c.url),
+		"method", method,
+		"attempt", attempt,
+		"duration", duration,
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	} else {
+		attrs = append(attrs, "status", resp.StatusCode, "redirects", len(c.redirects))
+	}
+	c.opts.logger().Debug("http request", attrs...)
+	return resp, err
+}
+
+func checkHTTP(url string, opts Options) LinkStatus {
+	c := newHTTPChecker(url, opts)
 
 	// Try HEAD request first (faster)
-	resp, err := client.Head(url)
+	resp, err := c.request(1, http.MethodHead)
 	if err != nil {
 		// If HEAD fails, try GET request (some servers don't support HEAD)
-		resp, err = client.Get(url)
+		resp, err = c.request(2, http.MethodGet)
 		if err != nil {
-			if strings.Contains(err.Error(), "timeout") {
-				return false, "Request timeout", 0
+			kind := classifyRequestError(err)
+			if kind == ErrorTimeout {
+				return LinkStatus{Link: url, Reason: "Request timeout", ErrorKind: kind}
 			}
-			return false, err.Error(), 0
+			return LinkStatus{Link: url, Reason: err.Error(), ErrorKind: kind}
 		}
 	}
 	defer resp.Body.Close()
 
+	status := LinkStatus{Link: url, StatusCode: resp.StatusCode}
+	if len(c.redirects) > 0 {
+		status.RedirectURL = resp.Request.URL.String()
+		status.RedirectCode = c.redirects[0]
+		status.PermanentRedirect = true
+		for _, code := range c.redirects {
+			if code != http.StatusMovedPermanently && code != http.StatusPermanentRedirect {
+				status.PermanentRedirect = false
+			}
+		}
+	}
+
 	// Consider 2xx and 3xx status codes as valid
 	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-		return true, "", resp.StatusCode
+		status.Valid = true
+		return status
 	}
 
-	return false, resp.Status, resp.StatusCode
+	status.Reason = resp.Status
+	status.ErrorKind = classifyStatus(resp.StatusCode)
+	return status
 }
 
-func checkFile(basePath, relPath string) (bool, string) {
-	var fullPath string
+// ResolvePath liefert den Dateipfad, auf den ein Dateilink zeigt. Anker und
+// Query-Parameter werden entfernt; für einen reinen Anker ist das Ergebnis leer.
+func ResolvePath(link string, opts Options) string {
+	relPath := link
+	if i := strings.IndexAny(relPath, "#?"); i >= 0 {
+		relPath = relPath[:i]
+	}
+	if relPath == "" {
+		return ""
+	}
+	if unescaped, err := url.PathUnescape(relPath); err == nil {
+		relPath = unescaped
+	}
 
-	if filepath.IsAbs(relPath) {
-		fullPath = relPath
-	} else {
-		fullPath = filepath.Join(basePath, relPath)
+	switch {
+	case strings.HasPrefix(relPath, "/") && opts.SiteRoot != "":
+		return filepath.Join(opts.SiteRoot, filepath.FromSlash(relPath))
+	case filepath.IsAbs(relPath):
+		return relPath
+	default:
+		return filepath.Join(opts.BasePath, filepath.FromSlash(relPath))
+	}
+}
+
+func checkFile(link string, opts Options) LinkStatus {
+	fullPath := ResolvePath(link, opts)
+	if fullPath == "" {
+		// Reiner Anker auf das aktuelle Dokument
+		return checkAnchor(link, opts.Document, opts)
 	}
 
-	_, err := os.Stat(fullPath)
+	info, err := os.Stat(fullPath)
 	if err != nil {
-		return false, err.Error()
+		return LinkStatus{Link: link, Reason: err.Error(), ErrorKind: classifyFileError(err)}
+	}
+
+	if info.IsDir() && len(opts.IndexFiles) > 0 {
+		for _, name := range opts.IndexFiles {
+			if _, err := os.Stat(filepath.Join(fullPath, name)); err == nil {
+				return LinkStatus{Link: link, Valid: true}
+			}
+		}
+		return LinkStatus{
+			Link:      link,
+			Reason:    fmt.Sprintf("directory %s has no %s", fullPath, strings.Join(opts.IndexFiles, " or ")),
+			ErrorKind: ErrorMissingIndex,
+		}
+	}
+	if info.IsDir() {
+		return LinkStatus{Link: link, Valid: true}
+	}
+	return checkAnchor(link, fullPath, opts)
+}
+
+// checkAnchor prüft, ob der Anker eines Dateilinks ein Sprungziel der Datei path ist.
+// Links ohne Anker sowie #top, das immer an den Anfang springt, sind gültig.
+func checkAnchor(link, path string, opts Options) LinkStatus {
+	i := strings.Index(link, "#")
+	if i < 0 || opts.Anchors == nil || path == "" {
+		return LinkStatus{Link: link, Valid: true}
+	}
+	fragment := link[i+1:]
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if fragment == "" || strings.EqualFold(fragment, "top") {
+		return LinkStatus{Link: link, Valid: true}
+	}
+
+	anchors, ok := opts.Anchors(path)
+	if !ok {
+		return LinkStatus{Link: link, Valid: true}
+	}
+	for _, anchor := range anchors {
+		if anchor == fragment {
+			return LinkStatus{Link: link, Valid: true}
+		}
+	}
+	return LinkStatus{
+		Link:      link,
+		Reason:    fmt.Sprintf("anchor #%s not found in %s", fragment, path),
+		ErrorKind: ErrorAnchorNotFound,
 	}
-	return true, ""
 }
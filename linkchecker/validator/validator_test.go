@@ -1,11 +1,19 @@
 package validator
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestValidateLinks_HTTP(t *testing.T) {
@@ -84,3 +92,354 @@ func TestValidateLinks_File(t *testing.T) {
 		t.Errorf("expected notfound.md to be invalid, got: %s", invalidResult.Reason)
 	}
 }
+
+func TestValidateLinksWithOptions_SiteRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "about"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "empty"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "about", "index.html"), []byte("<html></html>"), 0644); err != nil {
+		t.Fatalf("failed to create index file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "style.css"), []byte("body {}"), 0644); err != nil {
+		t.Fatalf("failed to create stylesheet: %v", err)
+	}
+
+	links := []string{
+		"/style.css",       // root-relativ
+		"/about/",          // Verzeichnis mit index.html
+		"../style.css#top", // relativ mit Anker
+		"#section",         // Anker im selben Dokument
+		"/empty/",          // Verzeichnis ohne index.html
+		"/missing.html",    // existiert nicht
+	}
+	results := ValidateLinksWithOptions(links, Options{
+		BasePath:   filepath.Join(root, "about"),
+		SiteRoot:   root,
+		IndexFiles: []string{"index.html"},
+		Workers:    2,
+	})
+
+	resultMap := make(map[string]LinkStatus)
+	for _, result := range results {
+		resultMap[result.Link] = result
+	}
+
+	for _, link := range links[:4] {
+		if !resultMap[link].Valid {
+			t.Errorf("expected %s to be valid, got: %s", link, resultMap[link].Reason)
+		}
+	}
+	for _, link := range links[4:] {
+		if resultMap[link].Valid {
+			t.Errorf("expected %s to be invalid", link)
+		}
+	}
+}
+
+func TestValidateLinksWithOptions_Anchors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "guide.md"), []byte("# Guide"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "logo.png"), []byte("png"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	anchors := map[string][]string{
+		filepath.Join(dir, "guide.md"): {"install", "über-uns"},
+		filepath.Join(dir, "index.md"): {"intro"},
+	}
+
+	links := []string{
+		"guide.md#install",       // Überschrift in der Zieldatei
+		"guide.md#%C3%BCber-uns", // kodierter Anker
+		"#intro",                 // Anker im selben Dokument
+		"guide.md#top",           // Anfang des Dokuments
+		"logo.png#frame",         // Datei ohne Sprungziele
+		"guide.md#missing",       // fehlender Anker
+		"#missing",               // fehlender Anker im selben Dokument
+	}
+	results := ValidateLinksWithOptions(links, Options{
+		BasePath: dir,
+		Document: filepath.Join(dir, "index.md"),
+		Workers:  2,
+		Anchors: func(path string) ([]string, bool) {
+			found, ok := anchors[path]
+			return found, ok
+		},
+	})
+
+	resultMap := make(map[string]LinkStatus)
+	for _, result := range results {
+		resultMap[result.Link] = result
+	}
+	for _, link := range links[:5] {
+		if !resultMap[link].Valid {
+			t.Errorf("expected %s to be valid, got: %s", link, resultMap[link].Reason)
+		}
+	}
+	for _, link := range links[5:] {
+		if resultMap[link].Valid || resultMap[link].ErrorKind != ErrorAnchorNotFound {
+			t.Errorf("expected %s to have a missing anchor, got %+v", link, resultMap[link])
+		}
+	}
+}
+
+func TestValidateLinks_ProtocolRelative(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/x.js" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	// Der Standard-Client soll dem Testzertifikat vertrauen
+	defer func(saved http.RoundTripper) { http.DefaultTransport = saved }(http.DefaultTransport)
+	http.DefaultTransport = ts.Client().Transport
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	results := ValidateLinksWithOptions([]string{"//" + host + "/x.js", "//" + host + "/missing.js"}, Options{
+		BasePath: t.TempDir(),
+		SiteRoot: t.TempDir(),
+		Timeout:  time.Second,
+		Workers:  2,
+	})
+
+	resultMap := make(map[string]LinkStatus)
+	for _, result := range results {
+		resultMap[result.Link] = result
+	}
+	if found := resultMap["//"+host+"/x.js"]; !found.Valid || found.StatusCode != http.StatusOK {
+		t.Errorf("expected protocol-relative link to be fetched over https, got %+v", found)
+	}
+	if missing := resultMap["//"+host+"/missing.js"]; missing.Valid || missing.ErrorKind != ErrorNotFound {
+		t.Errorf("expected 404 for missing protocol-relative link, got %+v", missing)
+	}
+}
+
+func TestValidateLinks_ErrorKind(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	want := map[string]ErrorKind{
+		ts.URL + "/gone":      ErrorNotFound,
+		ts.URL + "/forbidden": ErrorHTTPClient,
+		ts.URL + "/error":     ErrorHTTPServer,
+		"missing.md":          ErrorFileNotFound,
+	}
+	links := make([]string, 0, len(want))
+	for link := range want {
+		links = append(links, link)
+	}
+
+	for _, result := range ValidateLinks(links, t.TempDir()) {
+		if result.Valid {
+			t.Errorf("expected %s to be invalid", result.Link)
+		}
+		if result.ErrorKind != want[result.Link] {
+			t.Errorf("%s: expected error kind %q, got %q", result.Link, want[result.Link], result.ErrorKind)
+		}
+	}
+}
+
+func TestValidateLinks_Redirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/moved-again", http.StatusMovedPermanently)
+		case "/moved-again":
+			http.Redirect(w, r, "/target", http.StatusPermanentRedirect)
+		case "/temporary":
+			http.Redirect(w, r, "/target", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	results := ValidateLinks([]string{ts.URL + "/moved", ts.URL + "/temporary", ts.URL + "/target"}, "")
+	resultMap := make(map[string]LinkStatus)
+	for _, result := range results {
+		resultMap[result.Link] = result
+	}
+
+	moved := resultMap[ts.URL+"/moved"]
+	if !moved.Valid || moved.RedirectURL != ts.URL+"/target" || moved.RedirectCode != http.StatusMovedPermanently || !moved.PermanentRedirect {
+		t.Errorf("unexpected permanent redirect result: %+v", moved)
+	}
+	temporary := resultMap[ts.URL+"/temporary"]
+	if temporary.RedirectCode != http.StatusFound || temporary.PermanentRedirect {
+		t.Errorf("unexpected temporary redirect result: %+v", temporary)
+	}
+	if target := resultMap[ts.URL+"/target"]; target.RedirectURL != "" {
+		t.Errorf("expected no redirect for %s, got %+v", target.Link, target)
+	}
+}
+
+func TestValidateLinksWithOptions_OnResult(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("a"), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	var seen []string
+	var started atomic.Int32
+	results := ValidateLinksWithOptions([]string{"a.md", "b.md"}, Options{
+		BasePath: dir,
+		Timeout:  time.Second,
+		Workers:  2,
+		OnStart:  func(link string) { started.Add(1) },
+		OnResult: func(status LinkStatus) { seen = append(seen, status.Link) },
+	})
+
+	if started.Load() != 2 {
+		t.Errorf("expected OnStart for each link, got %d calls", started.Load())
+	}
+	if len(seen) != len(results) {
+		t.Fatalf("expected OnResult for each of %d results, got %d", len(results), len(seen))
+	}
+	for i, result := range results {
+		if seen[i] != result.Link {
+			t.Errorf("OnResult order differs from results at %d: %s != %s", i, seen[i], result.Link)
+		}
+	}
+}
+
+func TestValidateLinksWithOptions_Logger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ValidateLinksWithOptions([]string{server.URL + "/missing"}, Options{Timeout: time.Second, Workers: 1, Logger: logger})
+
+	var entries []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a request and a result entry, got %d:\n%s", len(entries), buf.String())
+	}
+
+	request := entries[0]
+	if request["msg"] != "http request" || request["method"] != "HEAD" || request["attempt"] != float64(1) ||
+		request["status"] != float64(404) || request["host"] != strings.TrimPrefix(server.URL, "http://") {
+		t.Errorf("unexpected request entry: %v", request)
+	}
+	result := entries[1]
+	if result["msg"] != "link checked" || result["level"] != "INFO" || result["error_kind"] != "not-found" || result["duration"] == nil {
+		t.Errorf("unexpected result entry: %v", result)
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	opts := Options{BasePath: filepath.Join("site", "docs"), SiteRoot: "site"}
+	tests := map[string]string{
+		"guide.md#setup":    filepath.Join("site", "docs", "guide.md"),
+		"../img/a%20b.png":  filepath.Join("site", "img", "a b.png"),
+		"/about/index.html": filepath.Join("site", "about", "index.html"),
+		"#top":              "",
+	}
+	for link, want := range tests {
+		if got := ResolvePath(link, opts); got != want {
+			t.Errorf("ResolvePath(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestValidateLinksWithOptions_Cache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := NewCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	opts := Options{Timeout: time.Second, Workers: 1, Cache: cache}
+	link := server.URL + "/missing"
+
+	for i := 0; i < 2; i++ {
+		results := ValidateLinksWithOptions([]string{link}, opts)
+		if len(results) != 1 || results[0].Valid || results[0].StatusCode != http.StatusNotFound {
+			t.Fatalf("unexpected results: %+v", results)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected the second check to be cached, got %d requests", requests.Load())
+	}
+
+	now = now.Add(time.Minute)
+	ValidateLinksWithOptions([]string{link}, opts)
+	if requests.Load() != 2 {
+		t.Errorf("expected an expired entry to be checked again, got %d requests", requests.Load())
+	}
+}
+
+func TestInspect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/target", http.StatusMovedPermanently)
+		case "/target":
+			w.Header().Set("X-Test", r.Method)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	insp := Inspect(server.URL+"/moved", Options{Timeout: time.Second})
+	if !insp.Status.Valid || insp.Status.RedirectURL != server.URL+"/target" || !insp.Status.PermanentRedirect {
+		t.Errorf("unexpected status: %+v", insp.Status)
+	}
+	if !strings.Contains(insp.Rule, "status 200") {
+		t.Errorf("unexpected rule: %s", insp.Rule)
+	}
+	if len(insp.Attempts) != 2 {
+		t.Fatalf("expected HEAD and a comparison GET, got %+v", insp.Attempts)
+	}
+	head, get := insp.Attempts[0], insp.Attempts[1]
+	if head.Method != http.MethodHead || head.Comparison || get.Method != http.MethodGet || !get.Comparison {
+		t.Errorf("unexpected attempts: %+v", insp.Attempts)
+	}
+	if len(head.Hops) != 2 || head.Hops[0].StatusCode != http.StatusMovedPermanently ||
+		head.Hops[0].Header.Get("Location") != "/target" || head.Hops[1].Header.Get("X-Test") != http.MethodHead {
+		t.Errorf("unexpected hops: %+v", head.Hops)
+	}
+	if head.Hops[0].RemoteAddr == "" || head.Hops[0].Timings.Total <= 0 {
+		t.Errorf("expected connection details and timings, got %+v", head.Hops[0])
+	}
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+	insp = Inspect(tlsServer.URL, Options{Timeout: time.Second})
+	if insp.Status.Valid || insp.Status.ErrorKind != ErrorTLS || len(insp.Attempts) != 2 || insp.Attempts[1].Comparison {
+		t.Errorf("expected an untrusted certificate to fail both attempts: %+v", insp)
+	}
+
+	info := tlsInfo(&tls.ConnectionState{Version: tls.VersionTLS13, PeerCertificates: []*x509.Certificate{tlsServer.Certificate()}})
+	if info.Version != "TLS 1.3" || len(info.Certificates) != 1 || info.Certificates[0].NotAfter.IsZero() {
+		t.Errorf("unexpected TLS info: %+v", info)
+	}
+}